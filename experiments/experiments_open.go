@@ -0,0 +1,77 @@
+package experiments
+
+import (
+    "fmt"
+    "math/rand"
+    "os"
+    "time"
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/open"
+    "github.com/aniagut/msc-bbs/sign"
+)
+
+// MeasureOpenTime measures the time taken for the Open function for different numbers of users
+// and saves the results to a file.
+func MeasureOpenTime() {
+    // Open the results file for writing
+    file, err := os.Create("experiments/results/open_time_results.txt")
+    if err != nil {
+        fmt.Printf("Error creating results file: %v\n", err)
+        return
+    }
+    defer file.Close()
+    // Write the header to the file
+    _, err = file.WriteString("UserCount,AverageOpenTime\n")
+    if err != nil {
+        fmt.Printf("Error writing to results file: %v\n", err)
+        return
+    }
+
+    // Define the number of users to test
+    userCounts := []int{100, 1000, 10000, 50000}
+    message := "Anna Maria Gut"
+    // Iterate over each user count
+    for _, userCount := range userCounts {
+        // Generate keys for the system
+        keyGenResult, err := keygen.KeyGen(userCount)
+        if err != nil {
+            fmt.Printf("Error during KeyGen for %d users: %v\n", userCount, err)
+            return
+        }
+        publicKey, secretManagerKey, users := keyGenResult.PublicKey, keyGenResult.SecretManagerKey, keyGenResult.Users
+        publicUsers := keygen.PublicUsers(keyGenResult)
+
+        // Sign the message with a randomly chosen member
+        signer := rand.Intn(userCount)
+        signature, err := sign.Sign(publicKey, users[signer], message)
+        if err != nil {
+            fmt.Printf("Error during Sign for %d users: %v\n", userCount, err)
+            return
+        }
+
+        var totalTime time.Duration
+        // Run Open 10 times and measure the total time
+        for i := 0; i < 10; i++ {
+            start := time.Now()
+            // Call Open
+            _, err := open.Open(publicKey, secretManagerKey, message, signature, publicUsers)
+            if err != nil {
+                fmt.Printf("Error during Open for %d users: %v\n", userCount, err)
+                return
+            }
+            // Measure the elapsed time
+            elapsed := time.Since(start)
+            totalTime += elapsed
+        }
+        // Calculate the average time
+        averageTime := totalTime / 10
+        // Print the results
+        fmt.Printf("Average Open time for %d users: %v\n", userCount, averageTime)
+        // Write the results to the file
+        _, err = file.WriteString(fmt.Sprintf("%d,%v\n", userCount, averageTime))
+        if err != nil {
+            fmt.Printf("Error writing to results file: %v\n", err)
+            return
+        }
+    }
+}
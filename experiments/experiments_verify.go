@@ -6,6 +6,7 @@ import (
     "time"
     "github.com/aniagut/msc-bbs/keygen"
     "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/utils"
     "github.com/aniagut/msc-bbs/verify"
 )
 
@@ -20,7 +21,7 @@ func MeasureVerifyTimeByMessageLength() {
     }
     defer file.Close()
     // Write the header to the file
-    _, err = file.WriteString("MessageLength,AverageVerifyTime\n")
+    _, err = file.WriteString("MessageLength,AverageVerifyTime,PairingsPerVerify\n")
     if err != nil {
         fmt.Printf("Error writing to results file: %v\n", err)
         return
@@ -35,6 +36,10 @@ func MeasureVerifyTimeByMessageLength() {
     // Extract the signing key and public parameters
     publicKey, signingUserKey := keyGenResult.PublicKey, keyGenResult.Users[0]
 
+    // Count the pairings computed by Verify
+    utils.Pairings.Enable()
+    defer utils.Pairings.Disable()
+
     // Define the length of the message string to test
     messageLengths := []int{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000}
     // Iterate over each message length
@@ -52,6 +57,7 @@ func MeasureVerifyTimeByMessageLength() {
             fmt.Printf("Error during Sign for length=%d: %v\n", length, err)
             return
         }
+        utils.Pairings.Reset()
         var totalTime time.Duration
         // Run Verify 10 times and measure the total time
         for i := 0; i < 10; i++ {
@@ -68,10 +74,11 @@ func MeasureVerifyTimeByMessageLength() {
         }
         // Calculate the average time
         averageTime := totalTime / 10
+        pairings := utils.Pairings.MillerLoops() / 10
         // Print the results
-        fmt.Printf("Average Verify time for message length=%d: %v\n", length, averageTime)
+        fmt.Printf("Average Verify time for message length=%d: %v (%d pairings)\n", length, averageTime, pairings)
         // Write the results to the file
-        _, err = file.WriteString(fmt.Sprintf("%d,%v\n", length, averageTime))
+        _, err = file.WriteString(fmt.Sprintf("%d,%v,%d\n", length, averageTime, pairings))
         if err != nil {
             fmt.Printf("Error writing to results file: %v\n", err)
             return
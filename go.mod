@@ -1,6 +1,6 @@
 module github.com/aniagut/msc-bbs
 
-go 1.18
+go 1.22.0
 
 require (
 	github.com/cloudflare/circl v1.6.2-0.20250604230827-acaa79c563ce
@@ -0,0 +1,102 @@
+// Package proof implements the R-value expressions of the proof of knowledge inside a BBS signature.
+// The signer evaluates them with the randomizers r and the verifier with the responses s,
+// adding the challenge terms on top (see R3Grouped), so sign and verify share a single definition of each R_i.
+// The package uses the BLS12-381 elliptic curve for cryptographic operations.
+package proof
+
+import (
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// R1 computes R1 = u^alpha.
+func R1(u *e.G1, alpha *e.Scalar) *e.G1 {
+    R1 := new(e.G1)
+    R1.ScalarMult(alpha, u)
+    return R1
+}
+
+// R2 computes R2 = v^beta.
+func R2(v *e.G1, beta *e.Scalar) *e.G1 {
+    R2 := new(e.G1)
+    R2.ScalarMult(beta, v)
+    return R2
+}
+
+// R3Terms returns the pairing terms of R3 = e(T3, g2)^x * e(h, w)^(-alpha - beta) * e(h, g2)^(-delta1 - delta2)
+// as the G1 arguments, G2 arguments and exponents expected by utils.MultiPair.
+func R3Terms(T3, h *e.G1, g2, w *e.G2, x, alpha, beta, delta1, delta2 *e.Scalar) ([]*e.G1, []*e.G2, []*e.Scalar) {
+    // Compute (-alpha - beta)
+    alphaBeta := new(e.Scalar)
+    alphaBeta.Add(alpha, beta)
+    alphaBeta.Neg()
+
+    // Compute (-delta1 - delta2)
+    delta := new(e.Scalar)
+    delta.Add(delta1, delta2)
+    delta.Neg()
+
+    return []*e.G1{T3, h, h}, []*e.G2{g2, w, g2}, []*e.Scalar{x, alphaBeta, delta}
+}
+
+// R3 computes R3 = e(T3, g2)^x * e(h, w)^(-alpha - beta) * e(h, g2)^(-delta1 - delta2).
+func R3(T3, h *e.G1, g2, w *e.G2, x, alpha, beta, delta1, delta2 *e.Scalar) (*e.Gt, error) {
+    return utils.MultiPair(R3Terms(T3, h, g2, w, x, alpha, beta, delta1, delta2))
+}
+
+// R3Grouped computes R3 * (e(g1, g2) / e(T3, w))^(-c) with the pairings grouped by their G2 argument:
+// e(T3^x * h^(-delta1 - delta2) * g1^(-c), g2) * e(h^(-alpha - beta) * T3^c, w).
+// The verifier evaluates it with the responses s and the challenge c; with c = 0 it equals R3.
+func R3Grouped(T3, h, g1 *e.G1, g2, w *e.G2, x, alpha, beta, delta1, delta2, c *e.Scalar) (*e.Gt, error) {
+    g1s, _, scalars := R3Terms(T3, h, g2, w, x, alpha, beta, delta1, delta2)
+
+    // Compute (-c)
+    minusC := new(e.Scalar)
+    minusC.Set(c)
+    minusC.Neg()
+
+    // Combine the G1 arguments paired with g2
+    left := new(e.G1)
+    left.ScalarMult(scalars[0], g1s[0])
+    term := new(e.G1)
+    term.ScalarMult(scalars[2], g1s[2])
+    left.Add(left, term)
+    term.ScalarMult(minusC, g1)
+    left.Add(left, term)
+
+    // Combine the G1 arguments paired with w
+    right := new(e.G1)
+    right.ScalarMult(scalars[1], g1s[1])
+    term.ScalarMult(c, T3)
+    right.Add(right, term)
+
+    one := new(e.Scalar)
+    one.SetOne()
+    return utils.MultiPair([]*e.G1{left, right}, []*e.G2{g2, w}, []*e.Scalar{one, one})
+}
+
+// R4 computes R4 = T1^x * u^(-delta1).
+func R4(T1, u *e.G1, x, delta1 *e.Scalar) *e.G1 {
+    return combine(T1, x, u, delta1)
+}
+
+// R5 computes R5 = T2^x * v^(-delta2).
+func R5(T2, v *e.G1, x, delta2 *e.Scalar) *e.G1 {
+    return combine(T2, x, v, delta2)
+}
+
+// combine computes T^x * b^(-delta).
+func combine(T *e.G1, x *e.Scalar, b *e.G1, delta *e.Scalar) *e.G1 {
+    Tx := new(e.G1)
+    Tx.ScalarMult(x, T)
+
+    minusDelta := new(e.Scalar)
+    minusDelta.Set(delta)
+    minusDelta.Neg()
+    bDelta := new(e.G1)
+    bDelta.ScalarMult(minusDelta, b)
+
+    R := new(e.G1)
+    R.Add(Tx, bDelta)
+    return R
+}
@@ -0,0 +1,96 @@
+package proof
+
+import (
+    "testing"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/utils"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestR4R5Relation tests that R4 and R5 evaluated with the secrets are the identity,
+// which is why the verifier needs no challenge terms for them.
+func TestR4R5Relation(t *testing.T) {
+    u := e.G1Generator()
+    v, err := utils.RandomG1Element()
+    assert.NoError(t, err, "RandomG1Element should not return an error")
+
+    var alpha, beta, x, delta1, delta2 e.Scalar
+    alpha.SetUint64(3)
+    beta.SetUint64(5)
+    x.SetUint64(7)
+    delta1.Mul(&alpha, &x)
+    delta2.Mul(&beta, &x)
+
+    // T1 = u^alpha, T2 = v^beta
+    T1 := R1(u, &alpha)
+    T2 := R2(&v, &beta)
+
+    assert.True(t, R4(T1, u, &x, &delta1).IsIdentity(), "T1^x * u^(-delta1) should be the identity")
+    assert.True(t, R5(T2, &v, &x, &delta2).IsIdentity(), "T2^x * v^(-delta2) should be the identity")
+}
+
+// TestR3 tests that R3 matches the product of its pairing terms.
+func TestR3(t *testing.T) {
+    g1 := e.G1Generator()
+    g2 := e.G2Generator()
+    var x, alpha, beta, delta1, delta2 e.Scalar
+    x.SetUint64(2)
+    alpha.SetUint64(3)
+    beta.SetUint64(4)
+    delta1.SetUint64(5)
+    delta2.SetUint64(6)
+
+    g1s, g2s, scalars := R3Terms(g1, g1, g2, g2, &x, &alpha, &beta, &delta1, &delta2)
+    assert.Len(t, g1s, 3, "R3 should have three pairing terms")
+    assert.Len(t, g2s, 3, "R3 should have three pairing terms")
+    assert.Len(t, scalars, 3, "R3 should have three pairing terms")
+
+    // With all bases equal, R3 = e(g1, g2)^(x - alpha - beta - delta1 - delta2) = e(g1, g2)^(-16)
+    R3, err := R3(g1, g1, g2, g2, &x, &alpha, &beta, &delta1, &delta2)
+    assert.NoError(t, err, "R3 should not return an error")
+    var exponent e.Scalar
+    exponent.SetUint64(16)
+    exponent.Neg()
+    expected := new(e.Gt)
+    expected.Exp(e.Pair(g1, g2), &exponent)
+    assert.True(t, expected.IsEqual(R3), "R3 should equal e(g1, g2)^(x - alpha - beta - delta1 - delta2)")
+}
+
+// TestR3Grouped tests that R3Grouped matches R3Terms with the challenge terms appended,
+// and that it equals R3 for a zero challenge.
+func TestR3Grouped(t *testing.T) {
+    g1 := e.G1Generator()
+    g2 := e.G2Generator()
+
+    values := make([]e.Scalar, 8)
+    for i := range values {
+        values[i].SetUint64(uint64(10 * (i + 1)))
+    }
+    T3 := new(e.G1)
+    T3.ScalarMult(&values[0], g1)
+    h := new(e.G1)
+    h.ScalarMult(&values[1], g1)
+    w := new(e.G2)
+    w.ScalarMult(&values[2], g2)
+    x, alpha, beta, delta1, delta2, c := &values[3], &values[4], &values[5], &values[6], &values[7], &values[3]
+
+    // (e(g1, g2) / e(T3, w))^(-c) appended to the three terms of R3
+    g1s, g2s, scalars := R3Terms(T3, h, g2, w, x, alpha, beta, delta1, delta2)
+    minusC := new(e.Scalar)
+    minusC.Set(c)
+    minusC.Neg()
+    expected, err := utils.MultiPair(append(g1s, g1, T3), append(g2s, g2, w), append(scalars, minusC, c))
+    assert.NoError(t, err, "MultiPair should not return an error")
+
+    grouped, err := R3Grouped(T3, h, g1, g2, w, x, alpha, beta, delta1, delta2, c)
+    assert.NoError(t, err, "R3Grouped should not return an error")
+    assert.True(t, expected.IsEqual(grouped), "R3Grouped should match the ungrouped product")
+
+    var zero e.Scalar
+    R3, err := R3(T3, h, g2, w, x, alpha, beta, delta1, delta2)
+    assert.NoError(t, err, "R3 should not return an error")
+    grouped, err = R3Grouped(T3, h, g1, g2, w, x, alpha, beta, delta1, delta2, &zero)
+    assert.NoError(t, err, "R3Grouped should not return an error")
+    assert.True(t, R3.IsEqual(grouped), "R3Grouped with a zero challenge should equal R3")
+}
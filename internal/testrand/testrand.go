@@ -0,0 +1,56 @@
+// Package testrand provides a deterministic source of randomness for reproducible tests.
+// Its output is fully determined by the seed, so it must only be used in tests and never
+// to generate keys or signatures that protect anything.
+package testrand
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/binary"
+)
+
+// Reader is a deterministic io.Reader that expands a seed into a byte stream.
+// Block i of the stream is HMAC-SHA256(seed, i) with i as an 8-byte big-endian counter,
+// so the stream depends only on the seed and not on any global state.
+// A Reader is not safe for concurrent use.
+type Reader struct {
+    seed    []byte
+    counter uint64
+    block   []byte
+}
+
+// New creates a Reader seeded with the given bytes.
+//
+// Parameters:
+//   - seed: The seed of the byte stream.
+//
+// Returns:
+//   - *Reader: The deterministic reader.
+func New(seed []byte) *Reader {
+    return &Reader{seed: append([]byte(nil), seed...)}
+}
+
+// Read fills p with the next bytes of the stream. It never fails.
+func (r *Reader) Read(p []byte) (int, error) {
+    n := 0
+    for n < len(p) {
+        if len(r.block) == 0 {
+            r.block = r.next()
+        }
+        copied := copy(p[n:], r.block)
+        r.block = r.block[copied:]
+        n += copied
+    }
+    return n, nil
+}
+
+// next computes the next block of the stream.
+func (r *Reader) next() []byte {
+    var counter [8]byte
+    binary.BigEndian.PutUint64(counter[:], r.counter)
+    r.counter++
+
+    mac := hmac.New(sha256.New, r.seed)
+    mac.Write(counter[:])
+    return mac.Sum(nil)
+}
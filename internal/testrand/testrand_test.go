@@ -0,0 +1,33 @@
+package testrand
+
+import (
+    "bytes"
+    "io"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+// TestReader tests that readers with the same seed produce the same stream, however it is read.
+func TestReader(t *testing.T) {
+    first := make([]byte, 100)
+    _, err := io.ReadFull(New([]byte("seed")), first)
+    assert.NoError(t, err, "Read should not return an error")
+
+    // Reading in uneven chunks yields the same bytes
+    second := New([]byte("seed"))
+    var chunked bytes.Buffer
+    for _, size := range []int{1, 31, 33, 35} {
+        chunk := make([]byte, size)
+        _, err := io.ReadFull(second, chunk)
+        assert.NoError(t, err, "Read should not return an error")
+        chunked.Write(chunk)
+    }
+    assert.Equal(t, first, chunked.Bytes(), "Readers with the same seed should produce identical streams")
+
+    // A different seed yields a different stream
+    other := make([]byte, 100)
+    _, err = io.ReadFull(New([]byte("other seed")), other)
+    assert.NoError(t, err, "Read should not return an error")
+    assert.NotEqual(t, first, other, "Readers with different seeds should produce different streams")
+}
@@ -7,77 +7,344 @@ import (
     e "github.com/cloudflare/circl/ecc/bls12381"
     "github.com/aniagut/msc-bbs/utils"
     "github.com/aniagut/msc-bbs/models"
+    "crypto/rand"
+    "errors"
+    "io"
     "sync"
     "fmt"
 )
 
+var (
+    // ErrWeakRandomness is returned when the source of randomness fails the self-test run by KeyGen.
+    ErrWeakRandomness = errors.New("weak randomness: random source failed the self-test")
+    // ErrInvalidGamma is returned when the master secret passed to KeyGenWithGamma is zero.
+    ErrInvalidGamma = errors.New("gamma must be a nonzero scalar")
+    // ErrInvalidOpenerData is returned when the opener's public data passed to KeyGenWithOpener is incomplete.
+    ErrInvalidOpenerData = errors.New("opener public data must contain h, u and v")
+    // ErrEqualEpsilons is returned when the source of randomness keeps producing epsilon1 = epsilon2, which would make u = v.
+    ErrEqualEpsilons = errors.New("epsilon1 and epsilon2 must differ")
+    // ErrNoUsers is returned when key generation is asked for fewer than one user.
+    ErrNoUsers = errors.New("the group must have at least one user")
+    // ErrDuplicateUserKey is returned when two users are assigned the same A, which Open could not tell apart.
+    ErrDuplicateUserKey = errors.New("two users share the same A")
+    // ErrIdentityBase is returned when the base g1 passed to user key generation is the identity.
+    ErrIdentityBase = errors.New("g1 must not be the identity")
+    // ErrIdentityUserKey is returned when a user's A would be the identity, i.e. gamma + x_i = 0.
+    ErrIdentityUserKey = errors.New("user key A must not be the identity")
+)
+
+// maxEpsilonRetries bounds how often drawEpsilons redraws epsilon2 when it equals epsilon1.
+// A healthy source repeats a scalar with negligible probability, so repeated collisions indicate a broken reader.
+const maxEpsilonRetries = 8
+
 // KeyGen generates the key material for the BBS signature scheme.
 // 
 // Parameters:
-//   - n: The number of users for whom SDH tuples will be generated.
+//   - n: The number of users for whom SDH tuples will be generated; it must be at least 1.
 //
 // Returns:
 //   - KeyGenResult: A struct containing the public key, user keys, and secret manager key.
 //   - error: An error if key generation fails.
 func KeyGen(n int) (models.KeyGenResult, error) {
-    
-    // 1. Select Generators g1 ∈ G1 and g2 ∈ G2
-    g1 := e.G1Generator()
-    g2 := e.G2Generator()
+    return KeyGenWithRand(n, rand.Reader)
+}
 
-    // 2. Select random h ∈ G1 (excluding identity element)
-    h, err := utils.RandomG1Element()
-    if err != nil {
+// KeyGenWithRand generates the key material for the BBS signature scheme, drawing every
+// random value from the given reader. A seeded reader reproduces the entire group,
+// which makes tests and debugging deterministic; production code should use KeyGen.
+//
+// Parameters:
+//   - n: The number of users for whom SDH tuples will be generated; it must be at least 1.
+//   - random: The source of randomness.
+//
+// Returns:
+//   - KeyGenResult: A struct containing the public key, user keys, and secret manager key.
+//   - error: An error if key generation fails.
+func KeyGenWithRand(n int, random io.Reader) (models.KeyGenResult, error) {
+    // Check that the source of randomness is not catastrophically broken
+    if err := checkRandomness(random); err != nil {
         return models.KeyGenResult{}, err
     }
 
-    // 3. Select random epsilon1, epsilon2 ∈ Zp*
-    epsilon1, err := utils.RandomScalar()
+    // Select gamma ∈ Zp*
+    gamma, err := utils.RandomScalarFromReader(random)
     if err != nil {
         return models.KeyGenResult{}, err
     }
-    epsilon2, err := utils.RandomScalar()
-    if err != nil {
+    return keyGenWithOpenerKey(n, gamma, random)
+}
+
+// KeyGenWithGamma generates the key material for the BBS signature scheme from an externally
+// generated master secret gamma, e.g. one held in an HSM or produced by MPC.
+// The public value w = g2^gamma and the SDH tuples are derived from gamma,
+// while h, epsilon1, epsilon2 and the x_i are drawn fresh.
+//
+// Parameters:
+//   - n: The number of users for whom SDH tuples will be generated; it must be at least 1.
+//   - gamma: The issuer's master secret.
+//
+// Returns:
+//   - KeyGenResult: A struct containing the public key, user keys, and secret manager key.
+//   - error: An error if gamma is zero or key generation fails.
+func KeyGenWithGamma(n int, gamma e.Scalar) (models.KeyGenResult, error) {
+    if gamma.IsZero() == 1 {
+        return models.KeyGenResult{}, ErrInvalidGamma
+    }
+    if err := checkRandomness(rand.Reader); err != nil {
         return models.KeyGenResult{}, err
     }
+    return keyGenWithOpenerKey(n, gamma, rand.Reader)
+}
 
-    // 4. Compute u, v ∈ G1 such that u^epsilon1 = v^epsilon2 = h
-    u, v := ComputeUAndV(g1, h, epsilon1, epsilon2)
+// KeyGenWithOpener generates the issuer's key material for a group whose opener key was generated
+// separately with GenerateOpenerKey. The issuer never sees epsilon1 and epsilon2,
+// so the SecretManagerKey of the result is left empty.
+//
+// Parameters:
+//   - n: The number of users for whom SDH tuples will be generated; it must be at least 1.
+//   - opener: The opener's public data (h, u, v).
+//
+// Returns:
+//   - KeyGenResult: A struct containing the public key and user keys.
+//   - error: An error if the opener data is incomplete or key generation fails.
+func KeyGenWithOpener(n int, opener models.OpenerPublicData) (models.KeyGenResult, error) {
+    if opener.H == nil || opener.U == nil || opener.V == nil {
+        return models.KeyGenResult{}, ErrInvalidOpenerData
+    }
+    if err := checkRandomness(rand.Reader); err != nil {
+        return models.KeyGenResult{}, err
+    }
 
-    // 5. Select gamma ∈ Zp* and compute w = g2^gamma
+    // Select gamma ∈ Zp*
     gamma, err := utils.RandomScalar()
     if err != nil {
         return models.KeyGenResult{}, err
     }
+    return keyGen(n, gamma, opener, rand.Reader)
+}
+
+// GenerateOpenerKey generates the opener's key material independently of the issuer.
+// It selects a random h ∈ G1 and epsilon1, epsilon2 ∈ Zp*, and derives u, v ∈ G1
+// such that u^epsilon1 = v^epsilon2 = h.
+//
+// Returns:
+//   - models.SecretManagerKey: The opener's secret key (epsilon1, epsilon2).
+//   - models.OpenerPublicData: The opener's public values (h, u, v) to pass to KeyGenWithOpener.
+//   - error: An error if key generation fails.
+func GenerateOpenerKey() (models.SecretManagerKey, models.OpenerPublicData, error) {
+    if err := checkRandomness(rand.Reader); err != nil {
+        return models.SecretManagerKey{}, models.OpenerPublicData{}, err
+    }
+    return generateOpenerKey(rand.Reader)
+}
+
+// RotateOpenerKey replaces the opener's key without re-issuing user credentials.
+// It draws fresh epsilon1, epsilon2 ∈ Zp* and recomputes u, v ∈ G1 from h such that
+// u^epsilon1 = v^epsilon2 = h. Gamma, w and every user's (A_i, x_i) are unaffected,
+// so existing user keys keep signing validly under the returned public key.
+//
+// Signatures made under the old u and v verify only under the old public key and
+// cannot be opened with the new secret manager key; keep the old key to open them.
+//
+// Parameters:
+//   - oldPub: The current public key of the system.
+//   - h: The element h ∈ G1 to derive u and v from, usually oldPub.H.
+//
+// Returns:
+//   - models.PublicKey: The public key with the new h, u and v.
+//   - models.SecretManagerKey: The new opener secret key (epsilon1, epsilon2).
+//   - error: An error if the public key or h is incomplete, or key generation fails.
+func RotateOpenerKey(oldPub models.PublicKey, h *e.G1) (models.PublicKey, models.SecretManagerKey, error) {
+    if oldPub.G1 == nil || oldPub.G2 == nil || oldPub.W == nil || h == nil || h.IsIdentity() {
+        return models.PublicKey{}, models.SecretManagerKey{}, ErrInvalidOpenerData
+    }
+    if err := checkRandomness(rand.Reader); err != nil {
+        return models.PublicKey{}, models.SecretManagerKey{}, err
+    }
+
+    // Select fresh epsilon1, epsilon2 ∈ Zp* with epsilon1 != epsilon2
+    epsilon1, epsilon2, err := drawEpsilons(rand.Reader)
+    if err != nil {
+        return models.PublicKey{}, models.SecretManagerKey{}, err
+    }
+
+    // Compute u, v ∈ G1 such that u^epsilon1 = v^epsilon2 = h
+    u, v := ComputeUAndV(oldPub.G1, *h, epsilon1, epsilon2)
+
+    newH := *h
+    newPub := oldPub
+    newPub.H = &newH
+    newPub.U = &u
+    newPub.V = &v
+
+    secretManagerKey := models.SecretManagerKey{
+        Epsilon1: epsilon1,
+        Epsilon2: epsilon2,
+    }
+    return newPub, secretManagerKey, nil
+}
+
+// PublicUsers strips the secret x_i from the users of a group, keeping only their A_i,
+// e.g. to hand the list of members to an opener while x_i stays with the issuer.
+//
+// Parameters:
+//   - result: The key material of the group.
+//
+// Returns:
+//   - []models.PublicUser: The public part of each user, in the same order as result.Users.
+func PublicUsers(result models.KeyGenResult) []models.PublicUser {
+    users := make([]models.PublicUser, len(result.Users))
+    for i, user := range result.Users {
+        users[i] = models.PublicUser{A: user.A}
+    }
+    return users
+}
+
+// KeyPairMatches reports whether the secret manager key belongs to the public key,
+// i.e. whether u^epsilon1 = v^epsilon2 = h as established by key generation.
+// A mismatch means the keys come from different groups and opening would fail.
+//
+// Parameters:
+//   - pk: The public key of the system.
+//   - sk: The secret manager key (epsilon1, epsilon2).
+//
+// Returns:
+//   - bool: True if the keys match, false otherwise.
+func KeyPairMatches(pk models.PublicKey, sk models.SecretManagerKey) bool {
+    if pk.H == nil || pk.U == nil || pk.V == nil {
+        return false
+    }
+
+    var uEpsilon1, vEpsilon2 e.G1
+    uEpsilon1.ScalarMult(&sk.Epsilon1, pk.U)
+    vEpsilon2.ScalarMult(&sk.Epsilon2, pk.V)
+    return uEpsilon1.IsEqual(pk.H) && vEpsilon2.IsEqual(pk.H)
+}
+
+// VerifySDHTuple reports whether a user key is a valid SDH tuple for the public key,
+// i.e. whether e(A_i, w * g2^x_i) = e(g1, g2). It computes one product of two pairings.
+//
+// Parameters:
+//   - pk: The public key of the system.
+//   - user: The user key (A_i, x_i).
+//
+// Returns:
+//   - bool: True if the tuple satisfies the SDH relation, false otherwise.
+func VerifySDHTuple(pk models.PublicKey, user models.User) bool {
+    if pk.G1 == nil || pk.G2 == nil || pk.W == nil || user.A == nil {
+        return false
+    }
+
+    // Compute w * g2^x_i
+    wg2x := new(e.G2)
+    wg2x.ScalarMult(&user.X, pk.G2)
+    wg2x.Add(wg2x, pk.W)
+
+    // Check e(A_i, w * g2^x_i) * e(g1, g2)^-1 = 1
+    one, minusOne := new(e.Scalar), new(e.Scalar)
+    one.SetOne()
+    minusOne.SetOne()
+    minusOne.Neg()
+    product, err := utils.MultiPair([]*e.G1{user.A, pk.G1}, []*e.G2{wg2x, pk.G2}, []*e.Scalar{one, minusOne})
+    if err != nil {
+        return false
+    }
+    return product.IsIdentity()
+}
+
+// generateOpenerKey generates the opener's key material from the given source of randomness.
+func generateOpenerKey(random io.Reader) (models.SecretManagerKey, models.OpenerPublicData, error) {
+    // 1. Select random h ∈ G1 (excluding identity element)
+    h, err := utils.RandomG1ElementFromReader(random)
+    if err != nil {
+        return models.SecretManagerKey{}, models.OpenerPublicData{}, err
+    }
+
+    // 2. Select random epsilon1, epsilon2 ∈ Zp* with epsilon1 != epsilon2
+    epsilon1, epsilon2, err := drawEpsilons(random)
+    if err != nil {
+        return models.SecretManagerKey{}, models.OpenerPublicData{}, err
+    }
+
+    // 3. Compute u, v ∈ G1 such that u^epsilon1 = v^epsilon2 = h
+    u, v := ComputeUAndV(e.G1Generator(), h, epsilon1, epsilon2)
+
+    secretManagerKey := models.SecretManagerKey{
+        Epsilon1: epsilon1,
+        Epsilon2: epsilon2,
+    }
+    opener := models.OpenerPublicData{
+        H: &h,
+        U: &u,
+        V: &v,
+    }
+    return secretManagerKey, opener, nil
+}
+
+// drawEpsilons draws the opener's secret scalars epsilon1, epsilon2 ∈ Zp*.
+// Equal epsilons would make u = v, so T1 and T2 would be built on the same base;
+// epsilon2 is redrawn until it differs from epsilon1, and ErrEqualEpsilons is returned
+// if the source keeps producing the same scalar.
+func drawEpsilons(random io.Reader) (e.Scalar, e.Scalar, error) {
+    epsilon1, err := utils.RandomScalarFromReader(random)
+    if err != nil {
+        return e.Scalar{}, e.Scalar{}, err
+    }
+    for i := 0; i < maxEpsilonRetries; i++ {
+        epsilon2, err := utils.RandomScalarFromReader(random)
+        if err != nil {
+            return e.Scalar{}, e.Scalar{}, err
+        }
+        if epsilon1.IsEqual(&epsilon2) == 0 {
+            return epsilon1, epsilon2, nil
+        }
+    }
+    return e.Scalar{}, e.Scalar{}, ErrEqualEpsilons
+}
+
+// keyGenWithOpenerKey generates the opener's and the issuer's key material for a validated gamma.
+func keyGenWithOpenerKey(n int, gamma e.Scalar, random io.Reader) (models.KeyGenResult, error) {
+    secretManagerKey, opener, err := generateOpenerKey(random)
+    if err != nil {
+        return models.KeyGenResult{}, err
+    }
+    result, err := keyGen(n, gamma, opener, random)
+    if err != nil {
+        return models.KeyGenResult{}, err
+    }
+    result.SecretManagerKey = secretManagerKey
+    return result, nil
+}
+
+// keyGen generates the issuer's key material for a validated gamma and the opener's public data.
+func keyGen(n int, gamma e.Scalar, opener models.OpenerPublicData, random io.Reader) (models.KeyGenResult, error) {
+    // 1. Select Generators g1 ∈ G1 and g2 ∈ G2
+    g1 := e.G1Generator()
+    g2 := e.G2Generator()
+
+    // 2. Compute w = g2^gamma
     w := ComputeW(g2, gamma)
 
-    // 6. Generate SDH tuples (A_i, x_i) for each user i
-    users, err := ComputeSDHTuples(n, g1, gamma)
+    // 3. Generate SDH tuples (A_i, x_i) for each user i
+    users, err := computeSDHTuples(n, g1, gamma, random)
     if err != nil {
         return models.KeyGenResult{}, err
     }
 
-    // 7. Construct the public key
+    // 4. Construct the public key
     publicKey := models.PublicKey{
         G1: g1,
         G2: g2,
-        H:  &h,
-        U:  &u,
-        V:  &v,
+        H:  opener.H,
+        U:  opener.U,
+        V:  opener.V,
         W:  &w,
     }
 
-    // 8. Construct the secret manager key
-    secretManagerKey := models.SecretManagerKey{
-        Epsilon1: epsilon1,
-        Epsilon2: epsilon2,
-    }
-
-    // 9. Return the result
+    // 5. Return the result
     return models.KeyGenResult{
-        PublicKey:       publicKey,
-        SecretManagerKey: secretManagerKey,
-        Users:           users,
+        PublicKey: publicKey,
+        Users:     users,
     }, nil
 }
 
@@ -110,50 +377,94 @@ func ComputeW(g2 *e.G2, gamma e.Scalar) e.G2 {
 }
 
 // ComputeSDHTuples generates n SDH tuples (A_i, x_i) for the users.
+// It returns ErrNoUsers if n is less than one, ErrIdentityBase if g1 is the identity,
+// and ErrIdentityUserKey if some gamma + x_i = 0.
 func ComputeSDHTuples(n int, g1 *e.G1, gamma e.Scalar) ([]models.User, error) {
+    return computeSDHTuples(n, g1, gamma, rand.Reader)
+}
+
+// computeSDHTuples generates n SDH tuples (A_i, x_i), drawing the x_i from the given source of randomness.
+// The x_i are drawn in user order so that a seeded reader yields the same users on every run,
+// and only the computation of the A_i runs concurrently.
+func computeSDHTuples(n int, g1 *e.G1, gamma e.Scalar, random io.Reader) ([]models.User, error) {
+    // A group without members cannot produce signatures, and a negative n cannot size the slice
+    if n < 1 {
+        return nil, fmt.Errorf("%w: n = %d", ErrNoUsers, n)
+    }
+
+    if err := checkBase(g1); err != nil {
+        return nil, err
+    }
+
     // Initialize a slice to store user data
     users := make([]models.User, n)
 
-    var wg sync.WaitGroup
-    errChan := make(chan error, n)
+    for i := 0; i < n; i++ {
+        // Select xI ∈ Zp* (a random scalar for the user)
+        xI, err := utils.RandomScalarFromReader(random)
+        if err != nil {
+            return nil, fmt.Errorf("failed to generate random scalar xI for user %d: %w", i, err)
+        }
+        users[i].X = xI
+    }
 
+    errs := make([]error, n)
+    var wg sync.WaitGroup
     for i := 0; i < n; i++ {
         wg.Add(1)
         go func(i int) {
             defer wg.Done()
-            
-            // Select xI ∈ Zp* (a random scalar for the user)
-            xI, err := utils.RandomScalar()
+
+            // Compute Ai = g1^(1 / (gamma + xI))
+            Ai, err := computeA(g1, gamma, users[i].X)
             if err != nil {
-                errChan <- fmt.Errorf("failed to generate random scalar xI for user %d: %w", i, err)
+                errs[i] = fmt.Errorf("user %d: %w", i, err)
                 return
             }
 
-            // Compute Ai = g1^(1 / (gamma + xI))
-            Ai := ComputeAi(g1, gamma, xI)
-
-            // Store the tuple (Ai, xI) in the users slice
-            users[i] = models.User{A: &Ai, X: xI}
+            // Store Ai next to xI in the users slice
+            users[i].A = &Ai
         }(i)
     }
     wg.Wait()
-    close(errChan)
 
-    // Check if any errors occurred in the goroutines
-    for err := range errChan {
+    for _, err := range errs {
         if err != nil {
             return nil, err
         }
     }
 
+    // Distinct x_i give distinct A_i, so a collision means the source of randomness repeated itself
+    if err := checkDistinctUsers(users); err != nil {
+        return nil, err
+    }
+
     return users, nil
 }
 
+// checkDistinctUsers returns ErrDuplicateUserKey if any two users share the same A.
+func checkDistinctUsers(users []models.User) error {
+    seen := make(map[string]int, len(users))
+    for i, user := range users {
+        key := string(utils.SerializeG1(user.A))
+        if j, ok := seen[key]; ok {
+            return fmt.Errorf("%w: users %d and %d", ErrDuplicateUserKey, j, i)
+        }
+        seen[key] = i
+    }
+    return nil
+}
+
 // OldComputeSDHTuples generates n SDH tuples (Ai, xI) for the users.
 // This is the old version of the function, which does not use goroutines.
 // It is kept for reference and may be removed in the future.
+// Like ComputeSDHTuples, it returns ErrNoUsers if n is less than one.
 // Deprecated: Use ComputeSDHTuples instead for concurrent execution.
 func OldComputeSDHTuples(n int, g1 *e.G1, gamma e.Scalar) ([]models.User, error) {
+    if n < 1 {
+        return nil, fmt.Errorf("%w: n = %d", ErrNoUsers, n)
+    }
+
     // Initialize a slice to store user data
     users := make([]models.User, n)
     
@@ -165,7 +476,10 @@ func OldComputeSDHTuples(n int, g1 *e.G1, gamma e.Scalar) ([]models.User, error)
         }
         
         // Compute Ai = g1^(1 / (gamma + xI))
-        Ai := ComputeAi(g1, gamma, xI)
+        Ai, err := computeA(g1, gamma, xI)
+        if err != nil {
+            return nil, fmt.Errorf("user %d: %w", i, err)
+        }
 
         // Store the tuple (Ai, xI) in the users slice
         users[i] = models.User{A: &Ai, X: xI}
@@ -175,6 +489,8 @@ func OldComputeSDHTuples(n int, g1 *e.G1, gamma e.Scalar) ([]models.User, error)
 }
 
 // ComputeAi computes Ai = g1^(1 / (gamma + xI)) for a given user.
+// It returns the identity if g1 is the identity or gamma + xI = 0; the key generation functions
+// call it through computeA, which rejects both cases.
 func ComputeAi(g1 *e.G1, gamma e.Scalar, xI e.Scalar) e.G1 {
     // Compute gamma + xI
     var gammaPlusX e.Scalar
@@ -188,4 +504,43 @@ func ComputeAi(g1 *e.G1, gamma e.Scalar, xI e.Scalar) e.G1 {
     Ai.ScalarMult(&gammaPlusX, g1)
 
     return Ai
-}
\ No newline at end of file
+}
+
+// computeA computes Ai = g1^(1 / (gamma + xI)) like ComputeAi,
+// returning an error instead of an identity base or an identity A.
+func computeA(g1 *e.G1, gamma e.Scalar, xI e.Scalar) (e.G1, error) {
+    if err := checkBase(g1); err != nil {
+        return e.G1{}, err
+    }
+    Ai := ComputeAi(g1, gamma, xI)
+    if Ai.IsIdentity() {
+        return e.G1{}, fmt.Errorf("%w: gamma + x_i = 0", ErrIdentityUserKey)
+    }
+    return Ai, nil
+}
+
+// checkBase returns ErrIdentityBase if g1 is missing or the identity, which would make every A the identity.
+func checkBase(g1 *e.G1) error {
+    if g1 == nil || g1.IsIdentity() {
+        return ErrIdentityBase
+    }
+    return nil
+}
+
+// checkRandomness draws two scalars from the given source and checks that they are nonzero and differ.
+// This is a cheap sanity gate against a broken source (e.g. one returning constant bytes),
+// not a statistical test of its quality.
+func checkRandomness(random io.Reader) error {
+    s1, err := utils.RandomScalarFromReader(random)
+    if err != nil {
+        return fmt.Errorf("%w: %v", ErrWeakRandomness, err)
+    }
+    s2, err := utils.RandomScalarFromReader(random)
+    if err != nil {
+        return fmt.Errorf("%w: %v", ErrWeakRandomness, err)
+    }
+    if s1.IsZero() == 1 || s2.IsZero() == 1 || s1.IsEqual(&s2) == 1 {
+        return ErrWeakRandomness
+    }
+    return nil
+}
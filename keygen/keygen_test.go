@@ -1,9 +1,16 @@
 package keygen
 
 import (
+    "bytes"
+    "crypto/rand"
+    "io"
     "testing"
 
     e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/internal/testrand"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/verify"
     "github.com/stretchr/testify/assert"
 )
 
@@ -128,4 +135,212 @@ func TestComputeAi(t *testing.T) {
     aCheck.ScalarMult(&gammaPlusX, g1)
 
     assert.True(t, aCheck.IsEqual(&Ai), "Ai should equal g1^(1 / (gamma + xI))")
-}
\ No newline at end of file
+}
+
+// constantReader is a broken source of randomness that always returns the same byte.
+type constantReader byte
+
+func (r constantReader) Read(p []byte) (int, error) {
+    for i := range p {
+        p[i] = byte(r)
+    }
+    return len(p), nil
+}
+
+// TestCheckRandomness tests that the randomness self-test detects a constant reader.
+func TestCheckRandomness(t *testing.T) {
+    // A healthy source passes the self-test
+    assert.NoError(t, checkRandomness(rand.Reader), "crypto/rand should pass the self-test")
+
+    // A reader returning constant bytes yields equal scalars
+    err := checkRandomness(constantReader(0x01))
+    assert.ErrorIs(t, err, ErrWeakRandomness, "A constant reader should fail the self-test")
+
+    // A reader returning only zeros cannot produce a nonzero scalar
+    err = checkRandomness(constantReader(0x00))
+    assert.ErrorIs(t, err, ErrWeakRandomness, "A zero reader should fail the self-test")
+
+    // A reader returning only 0xFF bytes never yields a candidate below the order
+    err = checkRandomness(constantReader(0xFF))
+    assert.ErrorIs(t, err, ErrWeakRandomness, "A 0xFF reader should fail the self-test")
+    _, err = KeyGenWithRand(1, constantReader(0xFF))
+    assert.ErrorIs(t, err, ErrWeakRandomness, "KeyGenWithRand should reject a 0xFF reader")
+}
+
+// TestDrawEpsilons tests that the opener key generation never accepts epsilon1 = epsilon2.
+func TestDrawEpsilons(t *testing.T) {
+    // A reader that repeats itself forever yields equal epsilons on every draw
+    _, _, err := generateOpenerKey(constantReader(0x01))
+    assert.ErrorIs(t, err, ErrEqualEpsilons, "generateOpenerKey should reject equal epsilons")
+
+    // A single collision is redrawn: h and both first epsilons come from the same constant bytes
+    repeated := bytes.Repeat([]byte{0x01}, 48+2*e.ScalarSize)
+    secretManagerKey, opener, err := generateOpenerKey(io.MultiReader(bytes.NewReader(repeated), rand.Reader))
+    assert.NoError(t, err, "generateOpenerKey should redraw a colliding epsilon2")
+    assert.True(t, secretManagerKey.Epsilon1.IsEqual(&secretManagerKey.Epsilon2) == 0, "epsilon1 and epsilon2 should differ")
+    assert.False(t, opener.U.IsEqual(opener.V), "u and v should differ")
+}
+
+// TestKeyGenNoUsers tests that key generation rejects groups with no users instead of panicking.
+func TestKeyGenNoUsers(t *testing.T) {
+    for _, n := range []int{0, -1} {
+        _, err := KeyGen(n)
+        assert.ErrorIs(t, err, ErrNoUsers, "KeyGen should reject n = %d", n)
+
+        _, err = ComputeSDHTuples(n, e.G1Generator(), e.Scalar{})
+        assert.ErrorIs(t, err, ErrNoUsers, "ComputeSDHTuples should reject n = %d", n)
+
+        _, err = OldComputeSDHTuples(n, e.G1Generator(), e.Scalar{})
+        assert.ErrorIs(t, err, ErrNoUsers, "OldComputeSDHTuples should reject n = %d", n)
+    }
+}
+
+// TestDuplicateUserKey tests that a source of randomness repeating x_i is caught before the users are returned.
+func TestDuplicateUserKey(t *testing.T) {
+    gamma := new(e.Scalar)
+    gamma.SetUint64(7)
+
+    // The first two x_i are drawn from the same constant bytes
+    repeated := bytes.Repeat([]byte{0x01}, 2*e.ScalarSize)
+    _, err := computeSDHTuples(3, e.G1Generator(), *gamma, io.MultiReader(bytes.NewReader(repeated), rand.Reader))
+    assert.ErrorIs(t, err, ErrDuplicateUserKey, "computeSDHTuples should reject users sharing A")
+
+    users, err := computeSDHTuples(3, e.G1Generator(), *gamma, rand.Reader)
+    assert.NoError(t, err, "computeSDHTuples should accept distinct users")
+    assert.Len(t, users, 3, "computeSDHTuples should return every user")
+}
+
+// TestIdentityBase tests that user key generation rejects an identity base and an identity A.
+func TestIdentityBase(t *testing.T) {
+    gamma := new(e.Scalar)
+    gamma.SetUint64(7)
+    identity := new(e.G1)
+    identity.SetIdentity()
+
+    _, err := ComputeSDHTuples(2, identity, *gamma)
+    assert.ErrorIs(t, err, ErrIdentityBase, "ComputeSDHTuples should reject an identity base")
+    _, err = OldComputeSDHTuples(2, identity, *gamma)
+    assert.ErrorIs(t, err, ErrIdentityBase, "OldComputeSDHTuples should reject an identity base")
+    _, err = newUserGenerator(2, identity, *gamma, rand.Reader)
+    assert.ErrorIs(t, err, ErrIdentityBase, "newUserGenerator should reject an identity base")
+
+    // x_i = -gamma makes 1 / (gamma + x_i) zero and A the identity
+    xI := new(e.Scalar)
+    xI.Set(gamma)
+    xI.Neg()
+    _, err = computeA(e.G1Generator(), *gamma, *xI)
+    assert.ErrorIs(t, err, ErrIdentityUserKey, "computeA should reject an identity A")
+
+    Ai, err := computeA(e.G1Generator(), *gamma, *gamma)
+    assert.NoError(t, err, "computeA should accept a regular user")
+    expected := ComputeAi(e.G1Generator(), *gamma, *gamma)
+    assert.True(t, Ai.IsEqual(&expected), "computeA should agree with ComputeAi")
+}
+
+// TestVerifySDHTuple tests that generated tuples satisfy the SDH relation and perturbed ones do not.
+func TestVerifySDHTuple(t *testing.T) {
+    result, err := KeyGen(2)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    var gamma e.Scalar
+    gamma.SetUint64(42)
+    w := ComputeW(result.PublicKey.G2, gamma)
+    publicKey := result.PublicKey
+    publicKey.W = &w
+    users, err := ComputeSDHTuples(2, publicKey.G1, gamma)
+    assert.NoError(t, err, "ComputeSDHTuples should not return an error")
+    for _, user := range users {
+        assert.True(t, VerifySDHTuple(publicKey, user), "A tuple from ComputeSDHTuples should be valid")
+    }
+    assert.True(t, VerifySDHTuple(result.PublicKey, result.Users[0]), "A tuple from KeyGen should be valid")
+
+    // Perturbing A breaks the relation
+    perturbed := users[0]
+    A := new(e.G1)
+    A.Add(perturbed.A, publicKey.G1)
+    perturbed.A = A
+    assert.False(t, VerifySDHTuple(publicKey, perturbed), "A tuple with a perturbed A should be invalid")
+
+    // Tuples from another group, the identity and nil are rejected
+    assert.False(t, VerifySDHTuple(result.PublicKey, users[1]), "A tuple for another gamma should be invalid")
+    identity := new(e.G1)
+    identity.SetIdentity()
+    assert.False(t, VerifySDHTuple(publicKey, models.User{A: identity, X: users[0].X}), "The identity should be invalid")
+    assert.False(t, VerifySDHTuple(publicKey, models.User{}), "A missing A should be invalid")
+}
+
+// TestKeyGenWithGamma tests that two KeyGens with the same gamma produce the same w and compatible user keys.
+func TestKeyGenWithGamma(t *testing.T) {
+    gamma := e.Scalar{}
+    gamma.SetUint64(123456789)
+
+    first, err := KeyGenWithGamma(2, gamma)
+    assert.NoError(t, err, "KeyGenWithGamma should not return an error")
+    second, err := KeyGenWithGamma(2, gamma)
+    assert.NoError(t, err, "KeyGenWithGamma should not return an error")
+
+    assert.True(t, first.PublicKey.W.IsEqual(second.PublicKey.W), "The same gamma should produce the same w")
+
+    // Every user key satisfies e(A, w * g2^x) = e(g1, g2) under both public keys
+    for _, user := range append(first.Users, second.Users...) {
+        for _, publicKey := range []models.PublicKey{first.PublicKey, second.PublicKey} {
+            g2x := new(e.G2)
+            g2x.ScalarMult(&user.X, publicKey.G2)
+            g2x.Add(g2x, publicKey.W)
+            lhs := e.Pair(user.A, g2x)
+            rhs := e.Pair(publicKey.G1, publicKey.G2)
+            assert.True(t, lhs.IsEqual(rhs), "User keys should be compatible with w")
+        }
+    }
+
+    // A zero gamma is rejected
+    _, err = KeyGenWithGamma(2, e.Scalar{})
+    assert.ErrorIs(t, err, ErrInvalidGamma, "KeyGenWithGamma should reject a zero gamma")
+}
+
+// TestKeyGenWithRand tests that KeyGenWithRand reproduces the group from a seeded reader.
+func TestKeyGenWithRand(t *testing.T) {
+    first, err := KeyGenWithRand(3, testrand.New([]byte("42")))
+    assert.NoError(t, err, "KeyGenWithRand should not return an error")
+    second, err := KeyGenWithRand(3, testrand.New([]byte("42")))
+    assert.NoError(t, err, "KeyGenWithRand should not return an error")
+
+    // The same seed yields the same public key and users
+    assert.True(t, first.PublicKey.W.IsEqual(second.PublicKey.W), "w should be equal for the same seed")
+    assert.True(t, first.PublicKey.H.IsEqual(second.PublicKey.H), "h should be equal for the same seed")
+    for i := range first.Users {
+        assert.True(t, first.Users[i].A.IsEqual(second.Users[i].A), "A should be equal for the same seed")
+        assert.Equal(t, 1, first.Users[i].X.IsEqual(&second.Users[i].X), "x should be equal for the same seed")
+    }
+
+    // A different seed yields a different group
+    other, err := KeyGenWithRand(3, testrand.New([]byte("43")))
+    assert.NoError(t, err, "KeyGenWithRand should not return an error")
+    assert.False(t, first.PublicKey.W.IsEqual(other.PublicKey.W), "w should differ for a different seed")
+
+    // The reproduced keys sign and verify
+    signature, err := sign.Sign(first.PublicKey, first.Users[0], "Hello, world!")
+    assert.NoError(t, err, "Sign should not return an error")
+    valid, err := verify.Verify(second.PublicKey, "Hello, world!", signature)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.True(t, valid, "a signature under the first key should verify under the reproduced key")
+}
+
+// TestKeyPairMatches tests that KeyPairMatches accepts keys of the same group only.
+func TestKeyPairMatches(t *testing.T) {
+    first, err := KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    second, err := KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    assert.True(t, KeyPairMatches(first.PublicKey, first.SecretManagerKey), "keys of the same group should match")
+    assert.False(t, KeyPairMatches(first.PublicKey, second.SecretManagerKey), "keys of different groups should not match")
+
+    // A public key whose u comes from a different group does not match
+    mixed := first.PublicKey
+    mixed.U = second.PublicKey.U
+    assert.False(t, KeyPairMatches(mixed, first.SecretManagerKey), "a public key with a foreign u should not match")
+
+    // An incomplete public key does not match
+    assert.False(t, KeyPairMatches(models.PublicKey{}, first.SecretManagerKey), "an empty public key should not match")
+}
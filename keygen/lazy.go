@@ -0,0 +1,83 @@
+package keygen
+
+import (
+    "crypto/rand"
+    "fmt"
+    "io"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// UserGenerator yields the SDH tuples (A_i, x_i) of a group one at a time, so a caller writing
+// them to storage never holds all of them in memory. It is used like bufio.Scanner: call Next
+// until it returns false, then check Err. A UserGenerator must not be used concurrently.
+type UserGenerator struct {
+    g1        *e.G1
+    gamma     e.Scalar
+    random    io.Reader
+    remaining int
+    err       error
+}
+
+// KeyGenLazy creates a generator of n user keys for the master secret gamma.
+// The keys belong to every group whose w = g2^gamma, e.g. a public key from KeyGenWithGamma
+// or one assembled with ComputeW. The x_i are drawn in the same order as by ComputeSDHTuples.
+//
+// Parameters:
+//   - n: The number of user keys to generate; it must be at least 1.
+//   - gamma: The issuer's master secret.
+//
+// Returns:
+//   - *UserGenerator: The generator of the user keys.
+//   - error: ErrNoUsers if n is less than one, or ErrInvalidGamma if gamma is zero.
+func KeyGenLazy(n int, gamma e.Scalar) (*UserGenerator, error) {
+    return newUserGenerator(n, e.G1Generator(), gamma, rand.Reader)
+}
+
+// newUserGenerator creates a generator that draws the x_i from the given source of randomness.
+func newUserGenerator(n int, g1 *e.G1, gamma e.Scalar, random io.Reader) (*UserGenerator, error) {
+    if n < 1 {
+        return nil, fmt.Errorf("%w: n = %d", ErrNoUsers, n)
+    }
+    if gamma.IsZero() == 1 {
+        return nil, ErrInvalidGamma
+    }
+    if err := checkBase(g1); err != nil {
+        return nil, err
+    }
+    return &UserGenerator{g1: g1, gamma: gamma, random: random, remaining: n}, nil
+}
+
+// Next generates the next user key. It returns false once all n keys have been generated
+// or generating a key fails; Err reports which.
+//
+// Returns:
+//   - models.User: The next user key (A_i, x_i).
+//   - bool: True if a key was generated, false otherwise.
+func (g *UserGenerator) Next() (models.User, bool) {
+    if g.remaining == 0 || g.err != nil {
+        return models.User{}, false
+    }
+
+    // Select xI ∈ Zp* and compute Ai = g1^(1 / (gamma + xI))
+    xI, err := utils.RandomScalarFromReader(g.random)
+    if err != nil {
+        g.err = fmt.Errorf("failed to generate random scalar xI: %w", err)
+        return models.User{}, false
+    }
+    Ai, err := computeA(g.g1, g.gamma, xI)
+    if err != nil {
+        g.err = err
+        return models.User{}, false
+    }
+
+    g.remaining--
+    return models.User{A: &Ai, X: xI}, true
+}
+
+// Err returns the error that stopped the generator, or nil if it ran to completion.
+func (g *UserGenerator) Err() error {
+    return g.err
+}
@@ -0,0 +1,49 @@
+package keygen
+
+import (
+    "testing"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/internal/testrand"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestKeyGenLazy tests that the lazy user keys match the eager ComputeSDHTuples output.
+func TestKeyGenLazy(t *testing.T) {
+    var gamma e.Scalar
+    gamma.SetUint64(123456789)
+    g1 := e.G1Generator()
+
+    eager, err := computeSDHTuples(5, g1, gamma, testrand.New([]byte("878")))
+    assert.NoError(t, err, "computeSDHTuples should not return an error")
+
+    generator, err := newUserGenerator(5, g1, gamma, testrand.New([]byte("878")))
+    assert.NoError(t, err, "newUserGenerator should not return an error")
+    var lazy []models.User
+    for user, ok := generator.Next(); ok; user, ok = generator.Next() {
+        lazy = append(lazy, user)
+    }
+    assert.NoError(t, generator.Err(), "The generator should run to completion")
+
+    assert.Len(t, lazy, len(eager), "The generator should yield n users")
+    for i := range eager {
+        assert.True(t, lazy[i].A.IsEqual(eager[i].A), "User %d should have the same A", i)
+        assert.Equal(t, 1, lazy[i].X.IsEqual(&eager[i].X), "User %d should have the same x", i)
+    }
+    _, ok := generator.Next()
+    assert.False(t, ok, "The generator should stay exhausted")
+
+    // Invalid parameters are rejected
+    _, err = KeyGenLazy(0, gamma)
+    assert.ErrorIs(t, err, ErrNoUsers, "KeyGenLazy should reject n = 0")
+    _, err = KeyGenLazy(5, e.Scalar{})
+    assert.ErrorIs(t, err, ErrInvalidGamma, "KeyGenLazy should reject a zero gamma")
+
+    // A failing source of randomness stops the generator with an error
+    generator, err = newUserGenerator(5, g1, gamma, constantReader(0x00))
+    assert.NoError(t, err, "newUserGenerator should not return an error")
+    _, ok = generator.Next()
+    assert.False(t, ok, "The generator should stop when randomness fails")
+    assert.Error(t, generator.Err(), "Err should report the randomness failure")
+}
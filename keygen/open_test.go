@@ -0,0 +1,94 @@
+package keygen_test
+
+import (
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/open"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestPublicUsers tests that Open identifies the signer from the stripped list of public users.
+func TestPublicUsers(t *testing.T) {
+    result, err := keygen.KeyGen(3)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    publicUsers := keygen.PublicUsers(result)
+    assert.Len(t, publicUsers, len(result.Users), "There should be one public user per user")
+    for i := range publicUsers {
+        assert.True(t, publicUsers[i].A.IsEqual(result.Users[i].A), "Public user %d should keep A", i)
+    }
+
+    message := "Hello, world!"
+    signature, err := sign.Sign(result.PublicKey, result.Users[2], message)
+    assert.NoError(t, err, "Sign should not return an error")
+    index, err := open.Open(result.PublicKey, result.SecretManagerKey, message, signature, publicUsers)
+    assert.NoError(t, err, "Open should not return an error")
+    assert.Equal(t, 2, index, "Open should identify the signer from the public users")
+}
+
+// TestGenerateOpenerKey tests a separately generated opener key wired into signing, verifying and opening.
+func TestGenerateOpenerKey(t *testing.T) {
+    // The opener generates its key material
+    secretManagerKey, opener, err := keygen.GenerateOpenerKey()
+    assert.NoError(t, err, "GenerateOpenerKey should not return an error")
+
+    // The issuer generates the group from the opener's public data
+    result, err := keygen.KeyGenWithOpener(3, opener)
+    assert.NoError(t, err, "KeyGenWithOpener should not return an error")
+    assert.True(t, result.PublicKey.U.IsEqual(opener.U), "The public key should contain the opener's u")
+    assert.True(t, result.SecretManagerKey.Epsilon1.IsZero() == 1, "The issuer should not learn epsilon1")
+
+    message := "Hello, world!"
+    signature, err := sign.Sign(result.PublicKey, result.Users[2], message)
+    assert.NoError(t, err, "Sign should not return an error")
+
+    valid, err := verify.Verify(result.PublicKey, message, signature)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.True(t, valid, "The signature should verify")
+
+    index, err := open.Open(result.PublicKey, secretManagerKey, message, signature, keygen.PublicUsers(result))
+    assert.NoError(t, err, "Open should not return an error")
+    assert.Equal(t, 2, index, "The opener should identify the signer")
+
+    // Incomplete opener data is rejected
+    _, err = keygen.KeyGenWithOpener(3, models.OpenerPublicData{})
+    assert.ErrorIs(t, err, keygen.ErrInvalidOpenerData, "KeyGenWithOpener should reject incomplete opener data")
+}
+
+// TestRotateOpenerKey tests that a rotated opener key opens new signatures and leaves user keys valid.
+func TestRotateOpenerKey(t *testing.T) {
+    result, err := keygen.KeyGen(3)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    rotated, secretManagerKey, err := keygen.RotateOpenerKey(result.PublicKey, result.PublicKey.H)
+    assert.NoError(t, err, "RotateOpenerKey should not return an error")
+    assert.True(t, rotated.H.IsEqual(result.PublicKey.H), "h should be kept")
+    assert.True(t, rotated.W.IsEqual(result.PublicKey.W), "w should be kept")
+    assert.False(t, rotated.U.IsEqual(result.PublicKey.U), "u should be replaced")
+    assert.False(t, rotated.V.IsEqual(result.PublicKey.V), "v should be replaced")
+
+    // Existing user keys sign validly under the rotated key and the new opener key opens them
+    message := "Hello, world!"
+    signature, err := sign.Sign(rotated, result.Users[1], message)
+    assert.NoError(t, err, "Sign should not return an error")
+    valid, err := verify.Verify(rotated, message, signature)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.True(t, valid, "The signature should verify under the rotated key")
+    index, err := open.Open(rotated, secretManagerKey, message, signature, keygen.PublicUsers(result))
+    assert.NoError(t, err, "Open should not return an error")
+    assert.Equal(t, 1, index, "The new opener key should identify the signer")
+
+    // Signatures under the old key do not open with the new opener key
+    oldSignature, err := sign.Sign(result.PublicKey, result.Users[1], message)
+    assert.NoError(t, err, "Sign should not return an error")
+    _, err = open.Open(rotated, secretManagerKey, message, oldSignature, keygen.PublicUsers(result))
+    assert.Error(t, err, "Open should fail for a signature under the old key")
+
+    // A missing h is rejected
+    _, _, err = keygen.RotateOpenerKey(result.PublicKey, nil)
+    assert.ErrorIs(t, err, keygen.ErrInvalidOpenerData, "RotateOpenerKey should reject a nil h")
+}
@@ -33,7 +33,7 @@ func main() {
 
 	fmt.Println("Is signature verified? ", verified)
 
-	signer, err := open.Open(publicKey, secretManagerKey, "Anna Maria Gut", signature, users)
+	signer, err := open.Open(publicKey, secretManagerKey, "Anna Maria Gut", signature, keygen.PublicUsers(result))
 	if err != nil {
 		fmt.Println("Error: ", err)
 		return
@@ -0,0 +1,63 @@
+package models
+
+import (
+    "encoding"
+    "encoding/pem"
+    "fmt"
+)
+
+const (
+    // PublicKeyBlockType is the armor block type of an encoded public key.
+    PublicKeyBlockType = "BBS GROUP PUBLIC KEY"
+    // OpenerSecretKeyBlockType is the armor block type of an encoded secret manager key.
+    OpenerSecretKeyBlockType = "BBS OPENER SECRET KEY"
+)
+
+// EncodePublicKeyPEM encodes the public key as a PEM block of type PublicKeyBlockType.
+func EncodePublicKeyPEM(pk PublicKey) ([]byte, error) {
+    return encodePEM(PublicKeyBlockType, pk)
+}
+
+// DecodePublicKeyPEM decodes a public key from a PEM block of type PublicKeyBlockType.
+func DecodePublicKeyPEM(data []byte) (PublicKey, error) {
+    var pk PublicKey
+    if err := decodePEM(data, PublicKeyBlockType, &pk); err != nil {
+        return PublicKey{}, err
+    }
+    return pk, nil
+}
+
+// EncodeOpenerSecretKeyPEM encodes the secret manager key as a PEM block of type OpenerSecretKeyBlockType.
+func EncodeOpenerSecretKeyPEM(sk SecretManagerKey) ([]byte, error) {
+    return encodePEM(OpenerSecretKeyBlockType, sk)
+}
+
+// DecodeOpenerSecretKeyPEM decodes a secret manager key from a PEM block of type OpenerSecretKeyBlockType.
+func DecodeOpenerSecretKeyPEM(data []byte) (SecretManagerKey, error) {
+    var sk SecretManagerKey
+    if err := decodePEM(data, OpenerSecretKeyBlockType, &sk); err != nil {
+        return SecretManagerKey{}, err
+    }
+    return sk, nil
+}
+
+// encodePEM wraps the binary encoding of value in a PEM block of the given type.
+func encodePEM(blockType string, value encoding.BinaryMarshaler) ([]byte, error) {
+    data, err := value.MarshalBinary()
+    if err != nil {
+        return nil, err
+    }
+    return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), nil
+}
+
+// decodePEM decodes the first PEM block in data into value after checking its type.
+func decodePEM(data []byte, blockType string, value encoding.BinaryUnmarshaler) error {
+    block, _ := pem.Decode(data)
+    if block == nil {
+        return fmt.Errorf("%w: no PEM block found", ErrInvalidEncoding)
+    }
+    if block.Type != blockType {
+        return fmt.Errorf("%w: unexpected PEM block type %q, want %q", ErrInvalidEncoding, block.Type, blockType)
+    }
+    return value.UnmarshalBinary(block.Bytes)
+}
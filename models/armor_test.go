@@ -0,0 +1,70 @@
+package models_test
+
+import (
+    "bytes"
+    "encoding/pem"
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestPublicKeyPEM tests that a public key and an opener key survive a PEM round trip.
+func TestPublicKeyPEM(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    armored, err := models.EncodePublicKeyPEM(result.PublicKey)
+    assert.NoError(t, err, "EncodePublicKeyPEM should not return an error")
+    assert.True(t, bytes.HasPrefix(armored, []byte("-----BEGIN BBS GROUP PUBLIC KEY-----\n")), "the block should start with its header line")
+
+    publicKey, err := models.DecodePublicKeyPEM(armored)
+    assert.NoError(t, err, "DecodePublicKeyPEM should not return an error")
+    assert.True(t, publicKey.W.IsEqual(result.PublicKey.W), "w should round-trip")
+    assert.True(t, publicKey.H.IsEqual(result.PublicKey.H), "h should round-trip")
+
+    // A signature under the original key verifies under the decoded key
+    signature, err := sign.Sign(result.PublicKey, result.Users[0], "Hello, world!")
+    assert.NoError(t, err, "Sign should not return an error")
+    valid, err := verify.Verify(publicKey, "Hello, world!", signature)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.True(t, valid, "the decoded public key should verify signatures")
+
+    armored, err = models.EncodeOpenerSecretKeyPEM(result.SecretManagerKey)
+    assert.NoError(t, err, "EncodeOpenerSecretKeyPEM should not return an error")
+    secretManagerKey, err := models.DecodeOpenerSecretKeyPEM(armored)
+    assert.NoError(t, err, "DecodeOpenerSecretKeyPEM should not return an error")
+    assert.Equal(t, 1, secretManagerKey.Epsilon1.IsEqual(&result.SecretManagerKey.Epsilon1), "epsilon1 should round-trip")
+    assert.Equal(t, 1, secretManagerKey.Epsilon2.IsEqual(&result.SecretManagerKey.Epsilon2), "epsilon2 should round-trip")
+}
+
+// TestPublicKeyPEMWrongType tests that decoding rejects a block of the wrong type or with a corrupted body.
+func TestPublicKeyPEMWrongType(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    // An opener key is not accepted as a public key
+    armored, err := models.EncodeOpenerSecretKeyPEM(result.SecretManagerKey)
+    assert.NoError(t, err, "EncodeOpenerSecretKeyPEM should not return an error")
+    _, err = models.DecodePublicKeyPEM(armored)
+    assert.ErrorIs(t, err, models.ErrInvalidEncoding, "DecodePublicKeyPEM should reject an opener key block")
+
+    // A public key block with a different header is rejected
+    armored, err = models.EncodePublicKeyPEM(result.PublicKey)
+    assert.NoError(t, err, "EncodePublicKeyPEM should not return an error")
+    relabeled := bytes.ReplaceAll(armored, []byte("BBS GROUP PUBLIC KEY"), []byte("PUBLIC KEY"))
+    _, err = models.DecodePublicKeyPEM(relabeled)
+    assert.ErrorIs(t, err, models.ErrInvalidEncoding, "DecodePublicKeyPEM should reject a wrong block type")
+
+    // A block of the right type with a truncated body is rejected
+    truncated := pem.EncodeToMemory(&pem.Block{Type: models.PublicKeyBlockType, Bytes: make([]byte, 10)})
+    _, err = models.DecodePublicKeyPEM(truncated)
+    assert.ErrorIs(t, err, models.ErrInvalidEncoding, "DecodePublicKeyPEM should reject an invalid body")
+
+    // Data without a PEM block is rejected
+    _, err = models.DecodePublicKeyPEM([]byte("not a key"))
+    assert.ErrorIs(t, err, models.ErrInvalidEncoding, "DecodePublicKeyPEM should reject data without a block")
+}
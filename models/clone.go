@@ -0,0 +1,61 @@
+package models
+
+import (
+    e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// Clone returns a deep copy of the public key. Every element is copied into a freshly
+// allocated value, so the copy can be modified without affecting the original.
+func (pk PublicKey) Clone() PublicKey {
+    return PublicKey{
+        G1: cloneG1(pk.G1),
+        G2: cloneG2(pk.G2),
+        H:  cloneG1(pk.H),
+        U:  cloneG1(pk.U),
+        V:  cloneG1(pk.V),
+        W:  cloneG2(pk.W),
+    }
+}
+
+// Clone returns a deep copy of the signature. Every element is copied into a freshly
+// allocated value, so the copy can be modified without affecting the original.
+func (s Signature) Clone() Signature {
+    return Signature{
+        T1:      cloneG1(s.T1),
+        T2:      cloneG1(s.T2),
+        T3:      cloneG1(s.T3),
+        C:       s.C,
+        SAlpha:  cloneScalar(s.SAlpha),
+        SBeta:   cloneScalar(s.SBeta),
+        SX:      cloneScalar(s.SX),
+        SDelta1: cloneScalar(s.SDelta1),
+        SDelta2: cloneScalar(s.SDelta2),
+    }
+}
+
+// cloneG1 copies a G1 element, keeping nil as nil.
+func cloneG1(p *e.G1) *e.G1 {
+    if p == nil {
+        return nil
+    }
+    c := *p
+    return &c
+}
+
+// cloneG2 copies a G2 element, keeping nil as nil.
+func cloneG2(p *e.G2) *e.G2 {
+    if p == nil {
+        return nil
+    }
+    c := *p
+    return &c
+}
+
+// cloneScalar copies a scalar, keeping nil as nil.
+func cloneScalar(s *e.Scalar) *e.Scalar {
+    if s == nil {
+        return nil
+    }
+    c := *s
+    return &c
+}
@@ -0,0 +1,47 @@
+package models_test
+
+import (
+    "testing"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestClone tests that mutating a cloned public key or signature leaves the original unchanged.
+func TestClone(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    message := "Hello, world!"
+    signature, err := sign.Sign(result.PublicKey, result.Users[0], message)
+    assert.NoError(t, err, "Sign should not return an error")
+
+    publicKey := result.PublicKey.Clone()
+    assert.True(t, publicKey.H.IsEqual(result.PublicKey.H), "The clone should equal the original")
+    assert.NotSame(t, publicKey.H, result.PublicKey.H, "The clone should not share elements")
+    clone := signature.Clone()
+    assert.NotSame(t, clone.SX, signature.SX, "The clone should not share scalars")
+
+    // Mutate every kind of element of the clones in place
+    publicKey.H.Double()
+    publicKey.W.Double()
+    clone.T3.Double()
+    clone.SX.SetUint64(1)
+    clone.C.SetUint64(1)
+
+    valid, err := verify.Verify(result.PublicKey, message, signature)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.True(t, valid, "The original signature should still verify under the original key")
+    assert.False(t, publicKey.H.IsEqual(result.PublicKey.H), "Mutating the clone should not change the original")
+
+    // Nil elements stay nil
+    empty := models.Signature{}.Clone()
+    assert.Nil(t, empty.T1, "A nil element should clone to nil")
+    assert.Nil(t, models.PublicKey{}.Clone().G2, "A nil element should clone to nil")
+    var zero e.Scalar
+    assert.True(t, empty.C.IsEqual(&zero) == 1, "The zero challenge should clone to zero")
+}
@@ -0,0 +1,325 @@
+package models
+
+import (
+    "bytes"
+    "encoding/binary"
+    "errors"
+    "fmt"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// ErrInvalidEncoding is returned when serialized data cannot be decoded.
+var ErrInvalidEncoding = errors.New("invalid encoding")
+
+// signatureScalarCount is the number of scalars in a serialized signature (C and the five s-values).
+const signatureScalarCount = 6
+
+// EncodeOpts controls how group elements are encoded.
+// Compressed points are smaller but decoding them costs a square root,
+// while uncompressed points are twice as large and decode faster.
+type EncodeOpts struct {
+    Compressed bool
+}
+
+// MarshalBinary encodes the signature with compressed points.
+func (s Signature) MarshalBinary() ([]byte, error) {
+    return s.Encode(EncodeOpts{Compressed: true})
+}
+
+// Encode encodes the signature as T1 || T2 || T3 || C || SAlpha || SBeta || SX || SDelta1 || SDelta2,
+// with the points encoded according to opts and each scalar as 32 big-endian bytes.
+func (s Signature) Encode(opts EncodeOpts) ([]byte, error) {
+    if s.T1 == nil || s.T2 == nil || s.T3 == nil || s.SAlpha == nil || s.SBeta == nil || s.SX == nil || s.SDelta1 == nil || s.SDelta2 == nil {
+        return nil, fmt.Errorf("%w: signature has nil fields", ErrInvalidEncoding)
+    }
+
+    data := make([]byte, 0, signatureSize(opts))
+    for _, p := range []*e.G1{s.T1, s.T2, s.T3} {
+        data = append(data, encodeG1(p, opts)...)
+    }
+    for _, k := range []*e.Scalar{&s.C, s.SAlpha, s.SBeta, s.SX, s.SDelta1, s.SDelta2} {
+        b := utils.SerializeScalar(k)
+        data = append(data, b[:]...)
+    }
+    return data, nil
+}
+
+// UnmarshalBinary decodes a signature produced by Encode with either point encoding.
+// The encoding is detected from the length of the data, and every point is checked to be in G1.
+func (s *Signature) UnmarshalBinary(data []byte) error {
+    var opts EncodeOpts
+    switch len(data) {
+    case signatureSize(EncodeOpts{Compressed: true}):
+        opts.Compressed = true
+    case signatureSize(EncodeOpts{Compressed: false}):
+        opts.Compressed = false
+    default:
+        return fmt.Errorf("%w: unexpected signature length %d", ErrInvalidEncoding, len(data))
+    }
+
+    pointSize := g1Size(opts)
+    points := make([]*e.G1, 3)
+    for i := range points {
+        p, err := decodeG1(data[i*pointSize:(i+1)*pointSize], opts)
+        if err != nil {
+            return err
+        }
+        points[i] = p
+    }
+
+    scalars := make([]*e.Scalar, signatureScalarCount)
+    offset := 3 * pointSize
+    for i := range scalars {
+        k, err := utils.DeserializeScalar(data[offset : offset+e.ScalarSize])
+        if err != nil {
+            return fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+        }
+        scalars[i] = &k
+        offset += e.ScalarSize
+    }
+
+    *s = Signature{
+        T1:      points[0],
+        T2:      points[1],
+        T3:      points[2],
+        C:       *scalars[0],
+        SAlpha:  scalars[1],
+        SBeta:   scalars[2],
+        SX:      scalars[3],
+        SDelta1: scalars[4],
+        SDelta2: scalars[5],
+    }
+    return nil
+}
+
+// signatureSize returns the length of a signature encoded with the given options.
+func signatureSize(opts EncodeOpts) int {
+    return 3*g1Size(opts) + signatureScalarCount*e.ScalarSize
+}
+
+// g1Size returns the length of a G1 point encoded with the given options.
+func g1Size(opts EncodeOpts) int {
+    if opts.Compressed {
+        return e.G1SizeCompressed
+    }
+    return e.G1Size
+}
+
+// encodeG1 encodes a G1 point according to the given options.
+func encodeG1(p *e.G1, opts EncodeOpts) []byte {
+    if opts.Compressed {
+        return p.BytesCompressed()
+    }
+    return p.Bytes()
+}
+
+// decodeG1 decodes a G1 point and checks that its encoding matches the given options.
+func decodeG1(data []byte, opts EncodeOpts) (*e.G1, error) {
+    isCompressed := data[0]&0x80 != 0
+    if isCompressed != opts.Compressed {
+        return nil, fmt.Errorf("%w: mixed point encodings", ErrInvalidEncoding)
+    }
+    p := new(e.G1)
+    if err := p.SetBytes(data); err != nil {
+        return nil, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+    }
+    return p, nil
+}
+
+// publicKeySize is the length of a public key encoded with compressed points (g1, h, u, v in G1 and g2, w in G2).
+const publicKeySize = 4*e.G1SizeCompressed + 2*e.G2SizeCompressed
+
+// secretManagerKeySize is the length of an encoded secret manager key (epsilon1 and epsilon2).
+const secretManagerKeySize = 2 * e.ScalarSize
+
+// MarshalBinary encodes the public key as G1 || G2 || H || U || V || W with compressed points.
+func (pk PublicKey) MarshalBinary() ([]byte, error) {
+    if pk.G1 == nil || pk.G2 == nil || pk.H == nil || pk.U == nil || pk.V == nil || pk.W == nil {
+        return nil, fmt.Errorf("%w: public key has nil fields", ErrInvalidEncoding)
+    }
+
+    data := make([]byte, 0, publicKeySize)
+    data = append(data, pk.G1.BytesCompressed()...)
+    data = append(data, pk.G2.BytesCompressed()...)
+    for _, p := range []*e.G1{pk.H, pk.U, pk.V} {
+        data = append(data, p.BytesCompressed()...)
+    }
+    data = append(data, pk.W.BytesCompressed()...)
+    return data, nil
+}
+
+// UnmarshalBinary decodes a public key produced by MarshalBinary.
+// Every point is checked to be in its group.
+func (pk *PublicKey) UnmarshalBinary(data []byte) error {
+    if len(data) != publicKeySize {
+        return fmt.Errorf("%w: unexpected public key length %d", ErrInvalidEncoding, len(data))
+    }
+    opts := EncodeOpts{Compressed: true}
+
+    offset := 0
+    nextG1 := func() (*e.G1, error) {
+        p, err := decodeG1(data[offset:offset+e.G1SizeCompressed], opts)
+        offset += e.G1SizeCompressed
+        return p, err
+    }
+    nextG2 := func() (*e.G2, error) {
+        p := new(e.G2)
+        err := p.SetBytes(data[offset : offset+e.G2SizeCompressed])
+        offset += e.G2SizeCompressed
+        if err != nil {
+            return nil, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+        }
+        return p, nil
+    }
+
+    g1, err := nextG1()
+    if err != nil {
+        return err
+    }
+    g2, err := nextG2()
+    if err != nil {
+        return err
+    }
+    points := make([]*e.G1, 3)
+    for i := range points {
+        if points[i], err = nextG1(); err != nil {
+            return err
+        }
+    }
+    w, err := nextG2()
+    if err != nil {
+        return err
+    }
+
+    *pk = PublicKey{
+        G1: g1,
+        G2: g2,
+        H:  points[0],
+        U:  points[1],
+        V:  points[2],
+        W:  w,
+    }
+    return nil
+}
+
+// MarshalBinary encodes the secret manager key as Epsilon1 || Epsilon2, each as 32 big-endian bytes.
+func (sk SecretManagerKey) MarshalBinary() ([]byte, error) {
+    data := make([]byte, 0, secretManagerKeySize)
+    for _, k := range []*e.Scalar{&sk.Epsilon1, &sk.Epsilon2} {
+        b := utils.SerializeScalar(k)
+        data = append(data, b[:]...)
+    }
+    return data, nil
+}
+
+// UnmarshalBinary decodes a secret manager key produced by MarshalBinary.
+// Both scalars must be canonical and nonzero.
+func (sk *SecretManagerKey) UnmarshalBinary(data []byte) error {
+    if len(data) != secretManagerKeySize {
+        return fmt.Errorf("%w: unexpected secret manager key length %d", ErrInvalidEncoding, len(data))
+    }
+
+    var scalars [2]e.Scalar
+    for i := range scalars {
+        k, err := utils.DeserializeScalar(data[i*e.ScalarSize : (i+1)*e.ScalarSize])
+        if err != nil {
+            return fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+        }
+        scalars[i] = k
+        if scalars[i].IsZero() == 1 {
+            return fmt.Errorf("%w: secret manager key must not be zero", ErrInvalidEncoding)
+        }
+    }
+
+    *sk = SecretManagerKey{
+        Epsilon1: scalars[0],
+        Epsilon2: scalars[1],
+    }
+    return nil
+}
+
+// MarshalCompact encodes the signature with compressed points and each scalar as a uvarint length
+// followed by its big-endian bytes without leading zeros.
+//
+// None of the five s-values can be derived from the others: each is r + c * secret with an
+// independent randomizer r, and C is the hash output they are checked against. The only slack
+// left is in leading zero bytes, and since the scalars are close to uniform modulo a 255-bit order,
+// the compact form saves bytes only for the rare scalars with leading zeros and is otherwise up to
+// six bytes longer than MarshalBinary. It is meant for transports where such savings add up over
+// many signatures or where scalars are known to be small.
+func (s Signature) MarshalCompact() ([]byte, error) {
+    if s.T1 == nil || s.T2 == nil || s.T3 == nil || s.SAlpha == nil || s.SBeta == nil || s.SX == nil || s.SDelta1 == nil || s.SDelta2 == nil {
+        return nil, fmt.Errorf("%w: signature has nil fields", ErrInvalidEncoding)
+    }
+
+    opts := EncodeOpts{Compressed: true}
+    data := make([]byte, 0, signatureSize(opts)+signatureScalarCount)
+    for _, p := range []*e.G1{s.T1, s.T2, s.T3} {
+        data = append(data, encodeG1(p, opts)...)
+    }
+    for _, k := range []*e.Scalar{&s.C, s.SAlpha, s.SBeta, s.SX, s.SDelta1, s.SDelta2} {
+        b := utils.SerializeScalar(k)
+        trimmed := bytes.TrimLeft(b[:], "\x00")
+        data = binary.AppendUvarint(data, uint64(len(trimmed)))
+        data = append(data, trimmed...)
+    }
+    return data, nil
+}
+
+// UnmarshalCompact decodes a signature produced by MarshalCompact.
+// Scalars with leading zero bytes are rejected, so every signature has a single compact encoding.
+func (s *Signature) UnmarshalCompact(data []byte) error {
+    opts := EncodeOpts{Compressed: true}
+    pointSize := g1Size(opts)
+    if len(data) < 3*pointSize {
+        return fmt.Errorf("%w: compact signature is too short", ErrInvalidEncoding)
+    }
+
+    points := make([]*e.G1, 3)
+    for i := range points {
+        p, err := decodeG1(data[i*pointSize:(i+1)*pointSize], opts)
+        if err != nil {
+            return err
+        }
+        points[i] = p
+    }
+
+    rest := data[3*pointSize:]
+    scalars := make([]*e.Scalar, signatureScalarCount)
+    for i := range scalars {
+        length, n := binary.Uvarint(rest)
+        if n <= 0 || length > e.ScalarSize || uint64(len(rest)-n) < length {
+            return fmt.Errorf("%w: invalid compact scalar length", ErrInvalidEncoding)
+        }
+        trimmed := rest[n : n+int(length)]
+        if len(trimmed) > 0 && trimmed[0] == 0 {
+            return fmt.Errorf("%w: compact scalar has leading zeros", ErrInvalidEncoding)
+        }
+        padded := make([]byte, e.ScalarSize)
+        copy(padded[e.ScalarSize-len(trimmed):], trimmed)
+        k, err := utils.DeserializeScalar(padded)
+        if err != nil {
+            return fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+        }
+        scalars[i] = &k
+        rest = rest[n+int(length):]
+    }
+    if len(rest) != 0 {
+        return fmt.Errorf("%w: trailing data after compact signature", ErrInvalidEncoding)
+    }
+
+    *s = Signature{
+        T1:      points[0],
+        T2:      points[1],
+        T3:      points[2],
+        C:       *scalars[0],
+        SAlpha:  scalars[1],
+        SBeta:   scalars[2],
+        SX:      scalars[3],
+        SDelta1: scalars[4],
+        SDelta2: scalars[5],
+    }
+    return nil
+}
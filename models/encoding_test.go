@@ -0,0 +1,101 @@
+package models_test
+
+import (
+    "testing"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/utils"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestSignatureEncoding tests that a signature encoded with compressed and uncompressed points
+// decodes to the same signature and verifies.
+func TestSignatureEncoding(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    message := "Hello, world!"
+    signature, err := sign.Sign(result.PublicKey, result.Users[0], message)
+    assert.NoError(t, err, "Sign should not return an error")
+
+    compressed, err := signature.Encode(models.EncodeOpts{Compressed: true})
+    assert.NoError(t, err, "Encode should not return an error")
+    uncompressed, err := signature.Encode(models.EncodeOpts{Compressed: false})
+    assert.NoError(t, err, "Encode should not return an error")
+
+    // Each of the three points takes 48 more bytes when uncompressed
+    assert.Equal(t, 3*48+6*32, len(compressed), "Compressed signature should be 336 bytes")
+    assert.Equal(t, 3*96+6*32, len(uncompressed), "Uncompressed signature should be 480 bytes")
+
+    for _, data := range [][]byte{compressed, uncompressed} {
+        var decoded models.Signature
+        assert.NoError(t, decoded.UnmarshalBinary(data), "UnmarshalBinary should not return an error")
+        assert.True(t, decoded.T1.IsEqual(signature.T1), "T1 should survive the round trip")
+        assert.True(t, decoded.T2.IsEqual(signature.T2), "T2 should survive the round trip")
+        assert.True(t, decoded.T3.IsEqual(signature.T3), "T3 should survive the round trip")
+
+        valid, err := verify.Verify(result.PublicKey, message, decoded)
+        assert.NoError(t, err, "Verify should not return an error")
+        assert.True(t, valid, "The decoded signature should verify")
+    }
+
+    // MarshalBinary uses compressed points
+    data, err := signature.MarshalBinary()
+    assert.NoError(t, err, "MarshalBinary should not return an error")
+    assert.Equal(t, compressed, data, "MarshalBinary should use compressed points")
+
+    // Truncated data is rejected
+    var decoded models.Signature
+    assert.ErrorIs(t, decoded.UnmarshalBinary(compressed[:100]), models.ErrInvalidEncoding, "Truncated data should be rejected")
+
+    // A response value at or above the order is rejected instead of being reduced
+    unreduced := append([]byte(nil), compressed...)
+    copy(unreduced[3*48:3*48+32], e.Order())
+    err = decoded.UnmarshalBinary(unreduced)
+    assert.ErrorIs(t, err, models.ErrInvalidEncoding, "An unreduced scalar should be rejected")
+    assert.ErrorIs(t, err, utils.ErrNonCanonicalScalar, "An unreduced scalar should be reported as non-canonical")
+}
+
+// TestSignatureCompactEncoding tests that the compact encoding round-trips, verifies and trims leading zeros.
+func TestSignatureCompactEncoding(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    message := "Hello, world!"
+    signature, err := sign.Sign(result.PublicKey, result.Users[0], message)
+    assert.NoError(t, err, "Sign should not return an error")
+
+    compact, err := signature.MarshalCompact()
+    assert.NoError(t, err, "MarshalCompact should not return an error")
+    fixed, err := signature.MarshalBinary()
+    assert.NoError(t, err, "MarshalBinary should not return an error")
+    // Each scalar costs its trimmed length plus one length byte
+    assert.LessOrEqual(t, len(compact), len(fixed)+6, "The compact encoding should add at most one byte per scalar")
+
+    var decoded models.Signature
+    assert.NoError(t, decoded.UnmarshalCompact(compact), "UnmarshalCompact should not return an error")
+    valid, err := verify.Verify(result.PublicKey, message, decoded)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.True(t, valid, "The decoded signature should verify")
+
+    // Small scalars shrink to a few bytes each
+    var small e.Scalar
+    small.SetUint64(1)
+    tiny := decoded
+    tiny.C = small
+    tiny.SAlpha, tiny.SBeta, tiny.SX, tiny.SDelta1, tiny.SDelta2 = &small, &small, &small, &small, &small
+    compact, err = tiny.MarshalCompact()
+    assert.NoError(t, err, "MarshalCompact should not return an error")
+    assert.Equal(t, 3*48+6*2, len(compact), "Scalars equal to 1 should take two bytes each")
+    assert.NoError(t, decoded.UnmarshalCompact(compact), "UnmarshalCompact should not return an error")
+    assert.Equal(t, 1, decoded.SX.IsEqual(&small), "A small scalar should round-trip")
+
+    // Non-minimal and truncated encodings are rejected
+    padded := append(append([]byte(nil), compact[:3*48]...), 2, 0, 1)
+    assert.ErrorIs(t, decoded.UnmarshalCompact(padded), models.ErrInvalidEncoding, "Leading zeros should be rejected")
+    assert.ErrorIs(t, decoded.UnmarshalCompact(compact[:len(compact)-1]), models.ErrInvalidEncoding, "Truncated data should be rejected")
+}
@@ -10,6 +10,8 @@ import (
 // It contains the following elements:
 // - G1, G2: Generators of the elliptic curve groups G1 and G2.
 // - H, U, V, W: Additional public parameters used in the signature scheme.
+// Signing, verifying and opening only read the elements, so one PublicKey may be shared by
+// concurrent goroutines. Code that needs to modify an element must work on a copy.
 type PublicKey struct {
     G1 *e.G1
     G2 *e.G2
@@ -53,6 +55,14 @@ type User struct {
     X e.Scalar
 }
 
+// PublicUser represents the part of a user's key that the opener needs to identify the signer.
+// It contains the following elements:
+// - A: The G1 element associated with the user.
+// The secret scalar x_i is left out, so the list can be handed to openers without exposing it.
+type PublicUser struct {
+    A *e.G1
+}
+
 // KeyGenResult represents the result of the key generation process.
 // It contains the following elements:
 // - PublicKey: The public key of the system.
@@ -62,4 +72,59 @@ type KeyGenResult struct {
     PublicKey        PublicKey
     SecretManagerKey SecretManagerKey
     Users           []User
-}
\ No newline at end of file
+}
+
+// OpenProof represents a proof that the opener recovered A correctly from a signature.
+// It contains the following elements:
+// - A: The G1 element recovered from the signature.
+// - C: The challenge scalar derived from the hash of the public values and commitments.
+// - ZEpsilon1, ZEpsilon2: Response values proving knowledge of epsilon1 and epsilon2.
+type OpenProof struct {
+    A         *e.G1
+    C         e.Scalar
+    ZEpsilon1 *e.Scalar
+    ZEpsilon2 *e.Scalar
+}
+
+// LinkableSignature represents a BBS signature with a linkability tag.
+// It contains the following elements:
+// - Signature: The BBS signature.
+// - Tag: The per-member, per-epoch tag H(epoch)^x_i, proven consistent with the signature.
+type LinkableSignature struct {
+    Signature Signature
+    Tag       *e.G1
+}
+
+// OpenerPublicData represents the public values derived from the opener's secret key.
+// It contains the following elements:
+// - H: The random G1 element h.
+// - U, V: The G1 elements such that u^epsilon1 = v^epsilon2 = h.
+type OpenerPublicData struct {
+    H *e.G1
+    U *e.G1
+    V *e.G1
+}
+
+// AttributeSignature represents a BBS signature over a list of committed attributes.
+// It contains the following elements:
+// - Signature: The BBS signature, whose challenge binds the commitments.
+// - Commitments: The per-attribute commitments G_i^{H(attr_i)} * h^{r_i}.
+// - Openings: The blinding factors r_i of the attributes that are disclosed, by attribute index.
+type AttributeSignature struct {
+    Signature   Signature
+    Commitments []*e.G1
+    Openings    map[int]*e.Scalar
+}
+
+// Disclose returns a copy of the signature that keeps the openings of the given attribute indices only,
+// so that all other attributes stay hidden behind their commitments.
+func (s AttributeSignature) Disclose(indices ...int) AttributeSignature {
+    openings := make(map[int]*e.Scalar, len(indices))
+    for _, i := range indices {
+        if opening, ok := s.Openings[i]; ok {
+            openings[i] = opening
+        }
+    }
+    s.Openings = openings
+    return s
+}
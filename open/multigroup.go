@@ -0,0 +1,83 @@
+package open
+
+import (
+    "errors"
+    "fmt"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+var (
+    // ErrUnknownGroup is returned when a MultiGroup has no group with the requested ID.
+    ErrUnknownGroup = errors.New("unknown group")
+    // ErrDuplicateGroup is returned when a group ID is added to a MultiGroup twice.
+    ErrDuplicateGroup = errors.New("group already exists")
+    // ErrOpenerKeyMismatch is returned when a group's public key was not derived from the MultiGroup's opener key.
+    ErrOpenerKeyMismatch = errors.New("public key does not match the opener key")
+)
+
+// MultiGroup opens signatures of several independent groups that share one opener key,
+// e.g. groups generated with keygen.KeyGenWithOpener from the same opener data.
+// Each group keeps its own public key and users, and is opened with an indexed Opener.
+// AddGroup must not be called concurrently with Open.
+type MultiGroup struct {
+    secretManagerKey models.SecretManagerKey
+    groups           map[string]*Opener
+    opts             []utils.HashOption
+}
+
+// NewMultiGroup creates a MultiGroup without groups for the given opener key.
+//
+// Parameters:
+//   - secretManagerKey: The opener key shared by all groups.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - *MultiGroup: The empty MultiGroup.
+func NewMultiGroup(secretManagerKey models.SecretManagerKey, opts ...utils.HashOption) *MultiGroup {
+    return &MultiGroup{
+        secretManagerKey: secretManagerKey,
+        groups:           make(map[string]*Opener),
+        opts:             opts,
+    }
+}
+
+// AddGroup registers a group under the given ID.
+//
+// Parameters:
+//   - groupID: The ID of the group.
+//   - publicKey: The public key of the group.
+//   - users: The users of the group.
+//
+// Returns:
+//   - error: ErrDuplicateGroup if the ID is taken, or ErrOpenerKeyMismatch if the public key belongs to a different opener.
+func (g *MultiGroup) AddGroup(groupID string, publicKey models.PublicKey, users []models.PublicUser) error {
+    if _, ok := g.groups[groupID]; ok {
+        return fmt.Errorf("%w: %q", ErrDuplicateGroup, groupID)
+    }
+    if !keygen.KeyPairMatches(publicKey, g.secretManagerKey) {
+        return fmt.Errorf("%w: group %q", ErrOpenerKeyMismatch, groupID)
+    }
+    g.groups[groupID] = NewOpener(publicKey, g.secretManagerKey, users, g.opts...)
+    return nil
+}
+
+// Open identifies the signer of a message within the given group.
+//
+// Parameters:
+//   - groupID: The ID of the group the signature claims to come from.
+//   - m: The message that was signed.
+//   - signature: The signature to open.
+//
+// Returns:
+//   - int: The index of the user within the group who signed the message (0-based).
+//   - error: ErrUnknownGroup if the group is not registered, or an error if the verification or recovery fails.
+func (g *MultiGroup) Open(groupID string, m string, signature models.Signature) (int, error) {
+    opener, ok := g.groups[groupID]
+    if !ok {
+        return -1, fmt.Errorf("%w: %q", ErrUnknownGroup, groupID)
+    }
+    return opener.Open(m, signature)
+}
@@ -0,0 +1,48 @@
+package open
+
+import (
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestMultiGroupOpen tests that a signature is opened against its own group only.
+func TestMultiGroupOpen(t *testing.T) {
+    secretManagerKey, opener, err := keygen.GenerateOpenerKey()
+    assert.NoError(t, err, "GenerateOpenerKey should not return an error")
+    groupA, err := keygen.KeyGenWithOpener(3, opener)
+    assert.NoError(t, err, "KeyGenWithOpener should not return an error")
+    groupB, err := keygen.KeyGenWithOpener(4, opener)
+    assert.NoError(t, err, "KeyGenWithOpener should not return an error")
+
+    groups := NewMultiGroup(secretManagerKey)
+    assert.NoError(t, groups.AddGroup("A", groupA.PublicKey, keygen.PublicUsers(groupA)), "AddGroup should not return an error")
+    assert.NoError(t, groups.AddGroup("B", groupB.PublicKey, keygen.PublicUsers(groupB)), "AddGroup should not return an error")
+
+    message := "Hello, world!"
+    signature, err := sign.Sign(groupA.PublicKey, groupA.Users[2], message)
+    assert.NoError(t, err, "Sign should not return an error")
+
+    // The signature opens in group A
+    index, err := groups.Open("A", message, signature)
+    assert.NoError(t, err, "Open should not return an error")
+    assert.Equal(t, 2, index, "The signer should be user 2 of group A")
+
+    // It is not a valid signature of group B
+    _, err = groups.Open("B", message, signature)
+    assert.ErrorIs(t, err, ErrSignatureInvalid, "Open should reject a signature of another group")
+
+    // Unknown and duplicate groups are rejected
+    _, err = groups.Open("C", message, signature)
+    assert.ErrorIs(t, err, ErrUnknownGroup, "Open should reject an unknown group")
+    err = groups.AddGroup("A", groupA.PublicKey, keygen.PublicUsers(groupA))
+    assert.ErrorIs(t, err, ErrDuplicateGroup, "AddGroup should reject a duplicate group")
+
+    // A group of a different opener is rejected
+    other, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    err = groups.AddGroup("D", other.PublicKey, keygen.PublicUsers(other))
+    assert.ErrorIs(t, err, ErrOpenerKeyMismatch, "AddGroup should reject a group of a different opener")
+}
@@ -4,12 +4,21 @@
 package open
 
 import (
+    "errors"
     "fmt"
     e "github.com/cloudflare/circl/ecc/bls12381"
     "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
     "github.com/aniagut/msc-bbs/verify"
 )
 
+var (
+    // ErrSignatureInvalid is returned when the signature to open does not verify.
+    ErrSignatureInvalid = errors.New("signature verification failed")
+    // ErrSignerNotFound is returned when the recovered A matches none of the users.
+    ErrSignerNotFound = errors.New("no matching user found for the recovered public key")
+)
+
 // Open identifies the signer of a message by verifying the signature and recovering the user's public key.
 //
 // Parameters:
@@ -17,21 +26,22 @@ import (
 //   - secretManagerKey: The secret manager key used to recover the user's public key.
 //   - m: The message that was signed.
 //   - signature: The signature to verify.
-//   - users: A list of users with their private keys.
+//   - users: The public parts (A_i) of the users, e.g. from keygen.PublicUsers.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
 //
 // Returns:
 //   - int: The index of the user who signed the message (0-based).
 //   - error: An error if the verification or recovery fails.
-func Open(publicKey models.PublicKey, secretManagerKey models.SecretManagerKey, m string, signature models.Signature, users []models.User) (int, error) {
+func Open(publicKey models.PublicKey, secretManagerKey models.SecretManagerKey, m string, signature models.Signature, users []models.PublicUser, opts ...utils.HashOption) (int, error) {
     // Step 1: Verify the signature
-    isValid, err := verify.Verify(publicKey, m, signature)
+    isValid, err := verify.Verify(publicKey, m, signature, opts...)
     if err != nil {
         fmt.Println("Verification failed due to an error:", err)
         return -1, err
     }
     if !isValid {
         fmt.Println("Verification failed!")
-        return -1, fmt.Errorf("signature verification failed")
+        return -1, ErrSignatureInvalid
     }
 
     // Step 2: Recover the user's private key (A) from the signature
@@ -45,7 +55,7 @@ func Open(publicKey models.PublicKey, secretManagerKey models.SecretManagerKey,
         }
     }
     // If no match is found, return an error
-    return -1, fmt.Errorf("no matching user found for the recovered public key")
+    return -1, ErrSignerNotFound
 }
 
 // RecoverUserPrivateKey computes the user's private key (A) from the signature and the secret manager key.
@@ -75,4 +85,27 @@ func RecoverUserPrivateKey(secretManagerKey models.SecretManagerKey, signature m
     recoveredA.Add(signature.T3, sum)
 
     return recoveredA
-}
\ No newline at end of file
+}
+
+// RecoverUserPrivateKeyFast computes the same A as RecoverUserPrivateKey, with T1^epsilon1 * T2^epsilon2
+// evaluated in a single interleaved double scalar multiplication instead of two separate ones.
+// Its memory access pattern depends on epsilon1 and epsilon2 (see utils.DoubleScalarMult),
+// so it is meant for openers that do not have to resist cache-timing attacks.
+//
+// Parameters:
+//   - secretManagerKey: The secret manager key used for recovery.
+//   - signature: The signature containing the necessary components.
+//
+// Returns:
+//   - *e.G1: The recovered private key (A).
+func RecoverUserPrivateKeyFast(secretManagerKey models.SecretManagerKey, signature models.Signature) *e.G1 {
+    // Compute (T1^epsilon1 + T2^epsilon2)^(-1)
+    sum := utils.DoubleScalarMult(&secretManagerKey.Epsilon1, signature.T1, &secretManagerKey.Epsilon2, signature.T2)
+    sum.Neg()
+
+    // Compute A = T3 + (T1^epsilon1 + T2^epsilon2)^(-1)
+    recoveredA := new(e.G1)
+    recoveredA.Add(signature.T3, sum)
+
+    return recoveredA
+}
@@ -1,13 +1,16 @@
 package open
 
 import (
+    "errors"
     "math/big"
     "crypto/rand"
     "testing"
     "fmt"
 
     e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/keygen"
     "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/sign"
     "github.com/stretchr/testify/assert"
 )
 
@@ -108,4 +111,22 @@ func TestableOpen(publicKey models.PublicKey, secretManagerKey models.SecretMana
 
     // If no match is found, return an error
     return -1, fmt.Errorf("no matching user found for the recovered public key")
-}
\ No newline at end of file
+}
+
+// TestOpenErrors tests that Open wraps the sentinel errors on failure.
+func TestOpenErrors(t *testing.T) {
+    result, err := keygen.KeyGen(3)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    message := "Hello, world!"
+    signature, err := sign.Sign(result.PublicKey, result.Users[1], message)
+    assert.NoError(t, err, "Sign should not return an error")
+
+    // A signature on a different message does not verify
+    _, err = Open(result.PublicKey, result.SecretManagerKey, "Another message", signature, keygen.PublicUsers(result))
+    assert.True(t, errors.Is(err, ErrSignatureInvalid), "Open should return ErrSignatureInvalid for an invalid signature")
+
+    // The signer is not in the list of users
+    _, err = Open(result.PublicKey, result.SecretManagerKey, message, signature, keygen.PublicUsers(result)[2:])
+    assert.True(t, errors.Is(err, ErrSignerNotFound), "Open should return ErrSignerNotFound if no user matches")
+}
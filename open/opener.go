@@ -0,0 +1,94 @@
+package open
+
+import (
+    "crypto/sha256"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+    "github.com/aniagut/msc-bbs/verify"
+)
+
+// Opener opens signatures against a fixed group of users.
+// It indexes the users' A values once, so each open is a single lookup instead of a scan over all users.
+type Opener struct {
+    publicKey        models.PublicKey
+    secretManagerKey models.SecretManagerKey
+    index            map[string]int
+    opts             []utils.HashOption
+
+    // OpenHook, if set, is called after every open with the SHA-256 digest of the encoded signature,
+    // the returned index and error. It is called on failures too, so callers can keep an audit trail.
+    OpenHook func(signatureDigest []byte, resultIndex int, err error)
+}
+
+// NewOpener creates an Opener for the given group.
+//
+// Parameters:
+//   - publicKey: The public key of the system.
+//   - secretManagerKey: The secret manager key used to recover the user's public key.
+//   - users: The public parts (A_i) of the users, e.g. from keygen.PublicUsers.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - *Opener: The opener for the group.
+func NewOpener(publicKey models.PublicKey, secretManagerKey models.SecretManagerKey, users []models.PublicUser, opts ...utils.HashOption) *Opener {
+    index := make(map[string]int, len(users))
+    for i, user := range users {
+        index[string(utils.SerializeG1(user.A))] = i
+    }
+    return &Opener{
+        publicKey:        publicKey,
+        secretManagerKey: secretManagerKey,
+        index:            index,
+        opts:             opts,
+    }
+}
+
+// Open identifies the signer of a message, like the Open function, and reports the result to OpenHook.
+//
+// Parameters:
+//   - m: The message that was signed.
+//   - signature: The signature to open.
+//
+// Returns:
+//   - int: The index of the user who signed the message (0-based).
+//   - error: An error if the verification or recovery fails.
+func (o *Opener) Open(m string, signature models.Signature) (int, error) {
+    index, err := o.open(m, signature)
+    if o.OpenHook != nil {
+        o.OpenHook(signatureDigest(signature), index, err)
+    }
+    return index, err
+}
+
+// open verifies the signature, recovers A and looks it up in the index.
+func (o *Opener) open(m string, signature models.Signature) (int, error) {
+    // Step 1: Verify the signature
+    isValid, err := verify.Verify(o.publicKey, m, signature, o.opts...)
+    if err != nil {
+        return -1, err
+    }
+    if !isValid {
+        return -1, ErrSignatureInvalid
+    }
+
+    // Step 2: Recover the user's private key (A) from the signature
+    recoveredA := RecoverUserPrivateKey(o.secretManagerKey, signature)
+
+    // Step 3: Look up the recovered public key
+    i, ok := o.index[string(utils.SerializeG1(recoveredA))]
+    if !ok {
+        return -1, ErrSignerNotFound
+    }
+    return i, nil
+}
+
+// signatureDigest returns the SHA-256 digest of the encoded signature,
+// or nil if the signature cannot be encoded.
+func signatureDigest(signature models.Signature) []byte {
+    data, err := signature.MarshalBinary()
+    if err != nil {
+        return nil
+    }
+    digest := sha256.Sum256(data)
+    return digest[:]
+}
@@ -0,0 +1,72 @@
+package open
+
+import (
+    "crypto/sha256"
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestOpenerOpen tests that the Opener identifies the signer.
+func TestOpenerOpen(t *testing.T) {
+    result, err := keygen.KeyGen(5)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    message := "Hello, world!"
+    signature, err := sign.Sign(result.PublicKey, result.Users[3], message)
+    assert.NoError(t, err, "Sign should not return an error")
+
+    opener := NewOpener(result.PublicKey, result.SecretManagerKey, keygen.PublicUsers(result))
+    index, err := opener.Open(message, signature)
+    assert.NoError(t, err, "Open should not return an error")
+    assert.Equal(t, 3, index, "The signer index should be 3")
+}
+
+// TestOpenerOpenHook tests that OpenHook fires with the expected arguments on success and failure.
+func TestOpenerOpenHook(t *testing.T) {
+    result, err := keygen.KeyGen(3)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    message := "Hello, world!"
+    signature, err := sign.Sign(result.PublicKey, result.Users[1], message)
+    assert.NoError(t, err, "Sign should not return an error")
+    encoded, err := signature.MarshalBinary()
+    assert.NoError(t, err, "MarshalBinary should not return an error")
+    expectedDigest := sha256.Sum256(encoded)
+
+    type call struct {
+        digest []byte
+        index  int
+        err    error
+    }
+    var calls []call
+    hook := func(signatureDigest []byte, resultIndex int, err error) {
+        calls = append(calls, call{signatureDigest, resultIndex, err})
+    }
+
+    // Success
+    opener := NewOpener(result.PublicKey, result.SecretManagerKey, keygen.PublicUsers(result))
+    opener.OpenHook = hook
+    _, _ = opener.Open(message, signature)
+
+    // Verification failure
+    _, _ = opener.Open("Another message", signature)
+
+    // Signer not found
+    stranger := NewOpener(result.PublicKey, result.SecretManagerKey, keygen.PublicUsers(result)[2:])
+    stranger.OpenHook = hook
+    _, _ = stranger.Open(message, signature)
+
+    assert.Equal(t, 3, len(calls), "OpenHook should fire on every open")
+    for _, c := range calls {
+        assert.Equal(t, expectedDigest[:], c.digest, "OpenHook should receive the signature digest")
+    }
+    assert.Equal(t, 1, calls[0].index, "OpenHook should receive the signer index")
+    assert.NoError(t, calls[0].err, "OpenHook should receive no error on success")
+    assert.Equal(t, -1, calls[1].index, "OpenHook should receive -1 on failure")
+    assert.ErrorIs(t, calls[1].err, ErrSignatureInvalid, "OpenHook should receive the verification error")
+    assert.Equal(t, -1, calls[2].index, "OpenHook should receive -1 on failure")
+    assert.ErrorIs(t, calls[2].err, ErrSignerNotFound, "OpenHook should receive the not-found error")
+}
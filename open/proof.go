@@ -0,0 +1,250 @@
+package open
+
+import (
+    "errors"
+    "fmt"
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+    "github.com/aniagut/msc-bbs/verify"
+)
+
+// ErrMalformedOpenProof is returned when an open proof is missing A or one of its responses.
+var ErrMalformedOpenProof = errors.New("malformed open proof")
+
+// openProofDomain separates the open proof challenge from the signature challenge.
+const openProofDomain = "BBS-OPEN-PROOF"
+
+// OpenWithProof identifies the signer of a message and proves that the opening was done correctly.
+// The proof shows, without revealing epsilon1 and epsilon2, that the recovered A satisfies
+// A = T3 - (T1^epsilon1 + T2^epsilon2) for the same epsilon1 and epsilon2 that define
+// u^epsilon1 = v^epsilon2 = h in the public key, so anyone can audit the opener.
+//
+// Parameters:
+//   - publicKey: The public key of the system.
+//   - secretManagerKey: The secret manager key used to recover the user's public key.
+//   - m: The message that was signed.
+//   - signature: The signature to open.
+//   - users: The public parts (A_i) of the users, e.g. from keygen.PublicUsers.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - int: The index of the user who signed the message (0-based).
+//   - models.OpenProof: The proof of correct opening.
+//   - error: An error if the verification, recovery or proof generation fails.
+func OpenWithProof(publicKey models.PublicKey, secretManagerKey models.SecretManagerKey, m string, signature models.Signature, users []models.PublicUser, opts ...utils.HashOption) (int, models.OpenProof, error) {
+    // Step 1: Open the signature
+    index, err := Open(publicKey, secretManagerKey, m, signature, users, opts...)
+    if err != nil {
+        return -1, models.OpenProof{}, err
+    }
+
+    // Step 2: Prove that the A of the signer was recovered correctly
+    proof, err := proveOpening(publicKey, secretManagerKey, signature, users[index].A)
+    if err != nil {
+        return -1, models.OpenProof{}, err
+    }
+    return index, proof, nil
+}
+
+// VerifyOpenProof checks that the signature is valid and that the A included in the proof
+// was correctly recovered from it by the holder of the secret manager key.
+//
+// Parameters:
+//   - publicKey: The public key of the system.
+//   - m: The message that was signed.
+//   - signature: The opened signature.
+//   - proof: The proof of correct opening.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - bool: True if the signature and the proof are valid, false otherwise.
+//   - error: ErrMalformedOpenProof if the proof is incomplete, or an error if the verification process fails.
+func VerifyOpenProof(publicKey models.PublicKey, m string, signature models.Signature, proof models.OpenProof, opts ...utils.HashOption) (bool, error) {
+    // Reject partially constructed proofs before dereferencing their fields
+    if err := validateOpenProofShape(proof); err != nil {
+        return false, err
+    }
+
+    // Step 1: Verify the signature
+    isValid, err := verify.Verify(publicKey, m, signature, opts...)
+    if err != nil || !isValid {
+        return false, err
+    }
+
+    // Step 2: Verify the proof of correct opening
+    return verifyOpening(publicKey, signature, proof)
+}
+
+// ProveOpenCorrectness recovers A from the signature and proves, like OpenWithProof, that it was computed
+// as A = T3 - (T1^epsilon1 + T2^epsilon2) with the epsilon1 and epsilon2 that satisfy u^epsilon1 = v^epsilon2 = h.
+// It neither verifies the signature nor looks up the signer, so the opener can prove the decryption of any
+// signature to an auditor who holds no secrets, e.g. one that arrives without its message.
+//
+// Parameters:
+//   - publicKey: The public key of the system.
+//   - secretManagerKey: The secret manager key used to recover A.
+//   - signature: The signature to open.
+//
+// Returns:
+//   - *e.G1: The recovered A.
+//   - models.OpenProof: The proof that A was recovered correctly.
+//   - error: An error if the signature is malformed or the proof generation fails.
+func ProveOpenCorrectness(publicKey models.PublicKey, secretManagerKey models.SecretManagerKey, signature models.Signature) (*e.G1, models.OpenProof, error) {
+    if signature.T1 == nil || signature.T2 == nil || signature.T3 == nil {
+        return nil, models.OpenProof{}, fmt.Errorf("%w: T1, T2 and T3 are required", verify.ErrMalformedSignature)
+    }
+    A := RecoverUserPrivateKey(secretManagerKey, signature)
+    proof, err := proveOpening(publicKey, secretManagerKey, signature, A)
+    if err != nil {
+        return nil, models.OpenProof{}, err
+    }
+    return A, proof, nil
+}
+
+// VerifyOpenCorrectness checks a proof from ProveOpenCorrectness: that A is the value the proof was made for
+// and that it was recovered from T1, T2 and T3 with the epsilon1 and epsilon2 committed in u and v.
+// Unlike VerifyOpenProof it does not verify the signature itself.
+//
+// Parameters:
+//   - publicKey: The public key of the system.
+//   - signature: The opened signature.
+//   - A: The A the opener claims to have recovered.
+//   - proof: The proof of correct opening.
+//
+// Returns:
+//   - bool: True if the proof shows that A was recovered correctly, false otherwise.
+func VerifyOpenCorrectness(publicKey models.PublicKey, signature models.Signature, A *e.G1, proof models.OpenProof) bool {
+    if A == nil || proof.A == nil || !A.IsEqual(proof.A) {
+        return false
+    }
+    if signature.T1 == nil || signature.T2 == nil || signature.T3 == nil || validateOpenProofShape(proof) != nil {
+        return false
+    }
+    valid, err := verifyOpening(publicKey, signature, proof)
+    return err == nil && valid
+}
+
+// validateOpenProofShape returns ErrMalformedOpenProof if A or one of the responses of the proof is missing.
+func validateOpenProofShape(proof models.OpenProof) error {
+    if proof.A == nil {
+        return fmt.Errorf("%w: A is missing", ErrMalformedOpenProof)
+    }
+    if proof.ZEpsilon1 == nil || proof.ZEpsilon2 == nil {
+        return fmt.Errorf("%w: ZEpsilon1 and ZEpsilon2 are required", ErrMalformedOpenProof)
+    }
+    return nil
+}
+
+// verifyOpening checks the proof that proof.A = T3 - (T1^epsilon1 + T2^epsilon2).
+func verifyOpening(publicKey models.PublicKey, signature models.Signature, proof models.OpenProof) (bool, error) {
+    // Step 1: Recompute the commitments from the responses
+    // K1 = u^z1 * h^{-c}, K2 = v^z2 * h^{-c}, K3 = T1^z1 * T2^z2 * (T3 / A)^{-c}
+    minusC := new(e.Scalar)
+    minusC.Set(&proof.C)
+    minusC.Neg()
+
+    hMinusC := new(e.G1)
+    hMinusC.ScalarMult(minusC, publicKey.H)
+
+    K1 := new(e.G1)
+    K1.ScalarMult(proof.ZEpsilon1, publicKey.U)
+    K1.Add(K1, hMinusC)
+
+    K2 := new(e.G1)
+    K2.ScalarMult(proof.ZEpsilon2, publicKey.V)
+    K2.Add(K2, hMinusC)
+
+    K3 := computeDecryptionCommitment(signature.T1, signature.T2, proof.ZEpsilon1, proof.ZEpsilon2)
+    T3MinusA := decryptionTarget(signature.T3, proof.A)
+    T3MinusA.ScalarMult(minusC, T3MinusA)
+    K3.Add(K3, T3MinusA)
+
+    // Step 2: Recompute the challenge and compare it with the proof's challenge
+    c, err := openProofChallenge(publicKey, signature, proof.A, K1, K2, K3)
+    if err != nil {
+        return false, err
+    }
+    return utils.ScalarsEqual(&c, &proof.C), nil
+}
+
+// proveOpening generates the proof that A = T3 - (T1^epsilon1 + T2^epsilon2).
+func proveOpening(publicKey models.PublicKey, secretManagerKey models.SecretManagerKey, signature models.Signature, A *e.G1) (models.OpenProof, error) {
+    // Select random r1, r2 ∈ Zp*
+    r1, err := utils.RandomScalar()
+    if err != nil {
+        return models.OpenProof{}, err
+    }
+    r2, err := utils.RandomScalar()
+    if err != nil {
+        return models.OpenProof{}, err
+    }
+
+    // Compute the commitments K1 = u^r1, K2 = v^r2, K3 = T1^r1 * T2^r2
+    K1 := new(e.G1)
+    K1.ScalarMult(&r1, publicKey.U)
+    K2 := new(e.G1)
+    K2.ScalarMult(&r2, publicKey.V)
+    K3 := computeDecryptionCommitment(signature.T1, signature.T2, &r1, &r2)
+
+    // Compute the challenge c
+    c, err := openProofChallenge(publicKey, signature, A, K1, K2, K3)
+    if err != nil {
+        return models.OpenProof{}, err
+    }
+
+    // Compute the responses z1 = r1 + c * epsilon1, z2 = r2 + c * epsilon2
+    z1 := new(e.Scalar)
+    z1.Mul(&c, &secretManagerKey.Epsilon1)
+    z1.Add(z1, &r1)
+    z2 := new(e.Scalar)
+    z2.Mul(&c, &secretManagerKey.Epsilon2)
+    z2.Add(z2, &r2)
+
+    return models.OpenProof{
+        A:         A,
+        C:         c,
+        ZEpsilon1: z1,
+        ZEpsilon2: z2,
+    }, nil
+}
+
+// computeDecryptionCommitment computes T1^a * T2^b.
+func computeDecryptionCommitment(T1, T2 *e.G1, a, b *e.Scalar) *e.G1 {
+    T1a := new(e.G1)
+    T1a.ScalarMult(a, T1)
+    T2b := new(e.G1)
+    T2b.ScalarMult(b, T2)
+    T1a.Add(T1a, T2b)
+    return T1a
+}
+
+// decryptionTarget computes T3 / A, which equals T1^epsilon1 * T2^epsilon2 for a correct opening.
+func decryptionTarget(T3, A *e.G1) *e.G1 {
+    minusA := new(e.G1)
+    *minusA = *A
+    minusA.Neg()
+    target := new(e.G1)
+    target.Add(T3, minusA)
+    return target
+}
+
+// openProofChallenge hashes the public values and commitments of the open proof into a scalar.
+func openProofChallenge(publicKey models.PublicKey, signature models.Signature, A, K1, K2, K3 *e.G1) (e.Scalar, error) {
+    t := utils.NewTranscript(openProofDomain)
+    t.AppendG1("h", publicKey.H)
+    t.AppendG1("u", publicKey.U)
+    t.AppendG1("v", publicKey.V)
+    t.AppendG1("T1", signature.T1)
+    t.AppendG1("T2", signature.T2)
+    t.AppendG1("T3", signature.T3)
+    t.AppendG1("A", A)
+    t.AppendG1("K1", K1)
+    t.AppendG1("K2", K2)
+    t.AppendG1("K3", K3)
+    c, err := t.Challenge()
+    if err != nil {
+        return e.Scalar{}, fmt.Errorf("failed to compute open proof challenge: %w", err)
+    }
+    return c, nil
+}
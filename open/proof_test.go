@@ -0,0 +1,128 @@
+package open
+
+import (
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestOpenWithProof tests that a third party can verify the proof returned by OpenWithProof.
+func TestOpenWithProof(t *testing.T) {
+    result, err := keygen.KeyGen(3)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    message := "Hello, world!"
+    signature, err := sign.Sign(result.PublicKey, result.Users[1], message)
+    assert.NoError(t, err, "Sign should not return an error")
+
+    // The opener opens the signature and proves the result
+    index, proof, err := OpenWithProof(result.PublicKey, result.SecretManagerKey, message, signature, keygen.PublicUsers(result))
+    assert.NoError(t, err, "OpenWithProof should not return an error")
+    assert.Equal(t, 1, index, "The signer index should be 1")
+    assert.True(t, proof.A.IsEqual(result.Users[1].A), "The proof should reveal the signer's A")
+
+    // A third party verifies the proof with the public key only
+    valid, err := VerifyOpenProof(result.PublicKey, message, signature, proof)
+    assert.NoError(t, err, "VerifyOpenProof should not return an error")
+    assert.True(t, valid, "VerifyOpenProof should accept a correct opening")
+}
+
+// TestVerifyOpenProofLyingOpener tests that VerifyOpenProof rejects an opener claiming the wrong signer.
+func TestVerifyOpenProofLyingOpener(t *testing.T) {
+    result, err := keygen.KeyGen(3)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    message := "Hello, world!"
+    signature, err := sign.Sign(result.PublicKey, result.Users[1], message)
+    assert.NoError(t, err, "Sign should not return an error")
+
+    // The opener proves that the signature was made by user 2
+    proof, err := proveOpening(result.PublicKey, result.SecretManagerKey, signature, result.Users[2].A)
+    assert.NoError(t, err, "proveOpening should not return an error")
+
+    valid, err := VerifyOpenProof(result.PublicKey, message, signature, proof)
+    assert.NoError(t, err, "VerifyOpenProof should not return an error")
+    assert.False(t, valid, "VerifyOpenProof should reject a proof for the wrong signer")
+
+    // Swapping A in an honest proof must also be rejected
+    _, honest, err := OpenWithProof(result.PublicKey, result.SecretManagerKey, message, signature, keygen.PublicUsers(result))
+    assert.NoError(t, err, "OpenWithProof should not return an error")
+    honest.A = result.Users[0].A
+    valid, err = VerifyOpenProof(result.PublicKey, message, signature, honest)
+    assert.NoError(t, err, "VerifyOpenProof should not return an error")
+    assert.False(t, valid, "VerifyOpenProof should reject a proof with a replaced A")
+}
+
+// TestVerifyOpenProofMalformed tests that VerifyOpenProof rejects incomplete proofs with ErrMalformedOpenProof.
+func TestVerifyOpenProofMalformed(t *testing.T) {
+    result, err := keygen.KeyGen(3)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    message := "Hello, world!"
+    signature, err := sign.Sign(result.PublicKey, result.Users[1], message)
+    assert.NoError(t, err, "Sign should not return an error")
+    _, honest, err := OpenWithProof(result.PublicKey, result.SecretManagerKey, message, signature, keygen.PublicUsers(result))
+    assert.NoError(t, err, "OpenWithProof should not return an error")
+
+    withoutA := honest
+    withoutA.A = nil
+    withoutZEpsilon1 := honest
+    withoutZEpsilon1.ZEpsilon1 = nil
+    withoutZEpsilon2 := honest
+    withoutZEpsilon2.ZEpsilon2 = nil
+
+    for _, proof := range []models.OpenProof{{}, withoutA, withoutZEpsilon1, withoutZEpsilon2} {
+        valid, err := VerifyOpenProof(result.PublicKey, message, signature, proof)
+        assert.ErrorIs(t, err, ErrMalformedOpenProof, "VerifyOpenProof should reject an incomplete proof")
+        assert.False(t, valid, "VerifyOpenProof should not accept an incomplete proof")
+        assert.False(t, VerifyOpenCorrectness(result.PublicKey, signature, result.Users[1].A, proof), "VerifyOpenCorrectness should not accept an incomplete proof")
+    }
+}
+
+// TestOpenCorrectness tests that a proof from ProveOpenCorrectness verifies without the message.
+func TestOpenCorrectness(t *testing.T) {
+    result, err := keygen.KeyGen(3)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    signature, err := sign.Sign(result.PublicKey, result.Users[1], "Hello, world!")
+    assert.NoError(t, err, "Sign should not return an error")
+
+    A, proof, err := ProveOpenCorrectness(result.PublicKey, result.SecretManagerKey, signature)
+    assert.NoError(t, err, "ProveOpenCorrectness should not return an error")
+    assert.True(t, A.IsEqual(result.Users[1].A), "ProveOpenCorrectness should recover the signer's A")
+    assert.True(t, VerifyOpenCorrectness(result.PublicKey, signature, A, proof), "VerifyOpenCorrectness should accept an honest proof")
+}
+
+// TestOpenCorrectnessCheatingOpener tests that VerifyOpenCorrectness rejects an opener that did not decrypt honestly.
+func TestOpenCorrectnessCheatingOpener(t *testing.T) {
+    result, err := keygen.KeyGen(3)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    signature, err := sign.Sign(result.PublicKey, result.Users[1], "Hello, world!")
+    assert.NoError(t, err, "Sign should not return an error")
+
+    // A proof made for another user's A
+    proof, err := proveOpening(result.PublicKey, result.SecretManagerKey, signature, result.Users[2].A)
+    assert.NoError(t, err, "proveOpening should not return an error")
+    assert.False(t, VerifyOpenCorrectness(result.PublicKey, signature, result.Users[2].A, proof), "VerifyOpenCorrectness should reject a proof for the wrong A")
+
+    // An honest proof presented for a different A
+    A, honest, err := ProveOpenCorrectness(result.PublicKey, result.SecretManagerKey, signature)
+    assert.NoError(t, err, "ProveOpenCorrectness should not return an error")
+    assert.False(t, VerifyOpenCorrectness(result.PublicKey, signature, result.Users[0].A, honest), "VerifyOpenCorrectness should reject a claim that does not match the proof")
+
+    // A proof made with epsilons that do not belong to the public key
+    other, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    forged, err := proveOpening(result.PublicKey, other.SecretManagerKey, signature, A)
+    assert.NoError(t, err, "proveOpening should not return an error")
+    assert.False(t, VerifyOpenCorrectness(result.PublicKey, signature, A, forged), "VerifyOpenCorrectness should reject a proof made with the wrong epsilons")
+
+    // A proof for another signature
+    otherSignature, err := sign.Sign(result.PublicKey, result.Users[1], "Hello, world!")
+    assert.NoError(t, err, "Sign should not return an error")
+    assert.False(t, VerifyOpenCorrectness(result.PublicKey, otherSignature, A, honest), "VerifyOpenCorrectness should reject a proof for another signature")
+}
@@ -0,0 +1,53 @@
+package open
+
+import (
+    "testing"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestRecoverUserPrivateKeyFast tests that RecoverUserPrivateKeyFast recovers the same A as RecoverUserPrivateKey.
+func TestRecoverUserPrivateKeyFast(t *testing.T) {
+    result, err := keygen.KeyGen(3)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    for i, user := range result.Users {
+        signature, err := sign.Sign(result.PublicKey, user, "Hello, world!")
+        assert.NoError(t, err, "Sign should not return an error")
+
+        expected := RecoverUserPrivateKey(result.SecretManagerKey, signature)
+        recovered := RecoverUserPrivateKeyFast(result.SecretManagerKey, signature)
+        assert.Equal(t, expected.Bytes(), recovered.Bytes(), "RecoverUserPrivateKeyFast should be bit-identical for user %d", i)
+        assert.True(t, recovered.IsEqual(user.A), "RecoverUserPrivateKeyFast should recover the signer's A")
+    }
+}
+
+// BenchmarkRecoverUserPrivateKey compares RecoverUserPrivateKey with RecoverUserPrivateKeyFast.
+func BenchmarkRecoverUserPrivateKey(b *testing.B) {
+    result, err := keygen.KeyGen(1)
+    if err != nil {
+        b.Fatal(err)
+    }
+    signature, err := sign.Sign(result.PublicKey, result.Users[0], "Hello, world!")
+    if err != nil {
+        b.Fatal(err)
+    }
+
+    for _, bench := range []struct {
+        name    string
+        recover func(models.SecretManagerKey, models.Signature) *e.G1
+    }{
+        {"ScalarMult", RecoverUserPrivateKey},
+        {"DoubleScalarMult", RecoverUserPrivateKeyFast},
+    } {
+        b.Run(bench.name, func(b *testing.B) {
+            for i := 0; i < b.N; i++ {
+                bench.recover(result.SecretManagerKey, signature)
+            }
+        })
+    }
+}
@@ -0,0 +1,203 @@
+// Package revocation provides a pairing-based accumulator of revoked user keys for the BBS group signature scheme.
+// The revocation manager accumulates the A_i of revoked users into a single G1 value, and a user who is not
+// revoked obtains a non-membership witness that is checked with one product of three pairings,
+// however many users are revoked.
+//
+// The check takes the user key A_i in the clear and is not bound to a signature, so whoever runs it
+// learns who signed. It is meant for the group manager or opener, who recovers A_i by opening the
+// signature and then checks it against the accumulator. Ordinary verifiers must not run it: asking the
+// signer for A_i would defeat the anonymity of the group signature.
+// The package uses the BLS12-381 elliptic curve for cryptographic operations.
+package revocation
+
+import (
+    "errors"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+var (
+    // ErrAlreadyRevoked is returned when a user key is added to the accumulator twice.
+    ErrAlreadyRevoked = errors.New("user key is already revoked")
+    // ErrRevoked is returned when a non-membership witness is requested for a revoked user key.
+    ErrRevoked = errors.New("user key is revoked")
+    // ErrDegenerateKey is returned when a user key hashes to the negated accumulator secret.
+    ErrDegenerateKey = errors.New("user key cannot be accumulated")
+)
+
+// revocationDomain is the label under which user keys are hashed to accumulator elements.
+const revocationDomain = "BBS-REVOCATION"
+
+// Accumulator accumulates revoked user keys as V = g1^{∏(s + y_i)}, where s is the manager's secret
+// and y_i = H(A_i). The manager publishes V and g2^s and hands out non-membership witnesses:
+// for a key with y not in the set, d = ∏(y_i - y) is nonzero and W = (V * g1^{-d})^{1/(s + y)} satisfies
+// e(W, g2^s * g2^y) * e(g1, g2)^d = e(V, g2).
+// An Accumulator must not be used concurrently.
+type Accumulator struct {
+    secret    e.Scalar
+    publicKey e.G2
+    value     e.G1
+    members   []e.Scalar
+}
+
+// NonMembershipWitness proves that a user key is not in an accumulator value.
+// It contains the following elements:
+// - W: The G1 element (V * g1^{-d})^{1/(s + y)}.
+// - D: The nonzero scalar d = ∏(y_i - y).
+type NonMembershipWitness struct {
+    W *e.G1
+    D e.Scalar
+}
+
+// NewAccumulator creates an empty accumulator with a fresh secret.
+//
+// Returns:
+//   - *Accumulator: The empty accumulator, whose value is g1.
+//   - error: An error if the secret cannot be generated.
+func NewAccumulator() (*Accumulator, error) {
+    secret, err := utils.RandomScalar()
+    if err != nil {
+        return nil, err
+    }
+    acc := &Accumulator{secret: secret}
+    acc.publicKey.ScalarMult(&secret, e.G2Generator())
+    acc.value = *e.G1Generator()
+    return acc, nil
+}
+
+// Add revokes a user key, updating the accumulator value to V^{s + H(A)}.
+// Witnesses issued for earlier values fail against the new value and must be reissued.
+//
+// Parameters:
+//   - A: The user key A_i to revoke.
+//
+// Returns:
+//   - error: ErrAlreadyRevoked if A is already accumulated, or ErrDegenerateKey if s + H(A) = 0.
+func (acc *Accumulator) Add(A *e.G1) error {
+    y, err := element(A)
+    if err != nil {
+        return err
+    }
+    for i := range acc.members {
+        if acc.members[i].IsEqual(&y) == 1 {
+            return ErrAlreadyRevoked
+        }
+    }
+
+    exponent := new(e.Scalar)
+    exponent.Add(&acc.secret, &y)
+    if exponent.IsZero() == 1 {
+        return ErrDegenerateKey
+    }
+    acc.value.ScalarMult(exponent, &acc.value)
+    acc.members = append(acc.members, y)
+    return nil
+}
+
+// Value returns a copy of the current accumulator value V.
+func (acc *Accumulator) Value() *e.G1 {
+    value := acc.value
+    return &value
+}
+
+// PublicKey returns a copy of the accumulator public key g2^s.
+func (acc *Accumulator) PublicKey() *e.G2 {
+    publicKey := acc.publicKey
+    return &publicKey
+}
+
+// NonMembershipWitness issues a witness that A is not revoked under the current accumulator value.
+// Computing it takes one pass over the revoked keys; verifying it does not.
+//
+// Parameters:
+//   - A: The user key A_i to issue the witness for.
+//
+// Returns:
+//   - NonMembershipWitness: The witness (W, d).
+//   - error: ErrRevoked if A is accumulated, or ErrDegenerateKey if s + H(A) = 0.
+func (acc *Accumulator) NonMembershipWitness(A *e.G1) (NonMembershipWitness, error) {
+    y, err := element(A)
+    if err != nil {
+        return NonMembershipWitness{}, err
+    }
+
+    // d = f(-y) = ∏(y_i - y), which is zero exactly when y is accumulated
+    var d, diff e.Scalar
+    d.SetOne()
+    for i := range acc.members {
+        diff.Sub(&acc.members[i], &y)
+        d.Mul(&d, &diff)
+    }
+    if d.IsZero() == 1 {
+        return NonMembershipWitness{}, ErrRevoked
+    }
+
+    // W = (V * g1^{-d})^{1/(s + y)}
+    inverse := new(e.Scalar)
+    inverse.Add(&acc.secret, &y)
+    if inverse.IsZero() == 1 {
+        return NonMembershipWitness{}, ErrDegenerateKey
+    }
+    inverse.Inv(inverse)
+
+    minusD := new(e.Scalar)
+    minusD.Set(&d)
+    minusD.Neg()
+    W := new(e.G1)
+    W.ScalarMult(minusD, e.G1Generator())
+    W.Add(W, &acc.value)
+    W.ScalarMult(inverse, W)
+
+    return NonMembershipWitness{W: W, D: d}, nil
+}
+
+// VerifyNonMembership checks that A is not accumulated in value, i.e. that d != 0 and
+// e(W, g2^s * g2^y) * e(g1, g2)^d = e(V, g2) with y = H(A). It computes one product of three pairings.
+// A is the signer's key in the clear, as recovered by opening the signature, so the check is for the
+// manager or opener only; an ordinary verifier must not run it, since it would learn who signed.
+//
+// Parameters:
+//   - value: The accumulator value V the witness was issued for.
+//   - publicKey: The accumulator public key g2^s.
+//   - A: The user key A_i.
+//   - witness: The non-membership witness for A.
+//
+// Returns:
+//   - bool: True if the witness proves that A is not revoked, false otherwise.
+func VerifyNonMembership(value *e.G1, publicKey *e.G2, A *e.G1, witness NonMembershipWitness) bool {
+    if value == nil || publicKey == nil || A == nil || witness.W == nil || witness.D.IsZero() == 1 {
+        return false
+    }
+    y, err := element(A)
+    if err != nil {
+        return false
+    }
+
+    // Compute g2^s * g2^y
+    base := new(e.G2)
+    base.ScalarMult(&y, e.G2Generator())
+    base.Add(base, publicKey)
+
+    one, minusOne := new(e.Scalar), new(e.Scalar)
+    one.SetOne()
+    minusOne.SetOne()
+    minusOne.Neg()
+    product, err := utils.MultiPair(
+        []*e.G1{witness.W, e.G1Generator(), value},
+        []*e.G2{base, e.G2Generator(), e.G2Generator()},
+        []*e.Scalar{one, &witness.D, minusOne},
+    )
+    if err != nil {
+        return false
+    }
+    return product.IsIdentity()
+}
+
+// element hashes a user key to its accumulator element y = H(A).
+func element(A *e.G1) (e.Scalar, error) {
+    if A == nil {
+        return e.Scalar{}, utils.ErrNilElement
+    }
+    return utils.HashToScalarLabeled(utils.LabeledInput{Label: revocationDomain, Data: utils.SerializeG1(A)})
+}
@@ -0,0 +1,48 @@
+package revocation
+
+import (
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestAccumulator tests that valid members get witnesses that verify and revoked members do not.
+func TestAccumulator(t *testing.T) {
+    result, err := keygen.KeyGen(4)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    acc, err := NewAccumulator()
+    assert.NoError(t, err, "NewAccumulator should not return an error")
+
+    // Every user has a witness against the empty accumulator
+    valid := result.Users[0].A
+    revoked := result.Users[1].A
+    witness, err := acc.NonMembershipWitness(revoked)
+    assert.NoError(t, err, "A user should get a witness before revocation")
+    assert.True(t, VerifyNonMembership(acc.Value(), acc.PublicKey(), revoked, witness), "The witness should verify before revocation")
+    stale := acc.Value()
+
+    // Revoke users 1 and 2
+    assert.NoError(t, acc.Add(revoked), "Add should not return an error")
+    assert.NoError(t, acc.Add(result.Users[2].A), "Add should not return an error")
+    assert.ErrorIs(t, acc.Add(revoked), ErrAlreadyRevoked, "Add should reject a key that is already revoked")
+
+    // A valid member's witness verifies, but not for another key
+    witness, err = acc.NonMembershipWitness(valid)
+    assert.NoError(t, err, "NonMembershipWitness should not return an error for a valid member")
+    assert.True(t, VerifyNonMembership(acc.Value(), acc.PublicKey(), valid, witness), "The witness should verify for a valid member")
+    assert.False(t, VerifyNonMembership(acc.Value(), acc.PublicKey(), result.Users[3].A, witness), "The witness should not verify for another key")
+    assert.False(t, VerifyNonMembership(stale, acc.PublicKey(), valid, witness), "The witness should not verify against an older value")
+
+    // A revoked member gets no witness, and its old witness fails against the new value
+    _, err = acc.NonMembershipWitness(revoked)
+    assert.ErrorIs(t, err, ErrRevoked, "NonMembershipWitness should reject a revoked key")
+    oldWitness, err := (&Accumulator{secret: acc.secret, publicKey: acc.publicKey, value: *stale}).NonMembershipWitness(revoked)
+    assert.NoError(t, err, "The revoked key was not in the old value")
+    assert.False(t, VerifyNonMembership(acc.Value(), acc.PublicKey(), revoked, oldWitness), "A stale witness should not verify for a revoked key")
+
+    // A witness with d = 0 never verifies
+    witness.D.SetUint64(0)
+    assert.False(t, VerifyNonMembership(acc.Value(), acc.PublicKey(), valid, witness), "A zero d should be rejected")
+    assert.False(t, VerifyNonMembership(acc.Value(), acc.PublicKey(), nil, witness), "A nil key should be rejected")
+}
@@ -0,0 +1,56 @@
+package sign
+
+import (
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// SignAttributes generates a BBS signature over a list of attributes that can be disclosed selectively.
+// Each attribute attr_i is committed as G_i^{H(attr_i)} * h^{r_i} with its own generator G_i and a
+// fresh blinding factor r_i, and the commitments are bound into the challenge. The returned signature
+// carries all openings r_i; use Disclose to keep only those of the attributes to reveal.
+//
+// Parameters:
+//   - publicKey: The public key of the system.
+//   - userPrivateKey: The private key of the user signing the attributes.
+//   - attrs: The attributes to be signed.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - models.AttributeSignature: The generated signature, the commitments and all openings.
+//   - error: An error if the list of attributes is empty or the signing process fails.
+func SignAttributes(publicKey models.PublicKey, userPrivateKey models.User, attrs []string, opts ...utils.HashOption) (models.AttributeSignature, error) {
+    if len(attrs) == 0 {
+        return models.AttributeSignature{}, utils.ErrEmptyMessageVector
+    }
+
+    // Commit to each attribute with a fresh blinding factor
+    blindings, err := GenerateRandomScalars(len(attrs))
+    if err != nil {
+        return models.AttributeSignature{}, err
+    }
+    commitments := make([]*e.G1, len(attrs))
+    openings := make(map[int]*e.Scalar, len(attrs))
+    for i, attr := range attrs {
+        blinding := blindings[i]
+        commitments[i], err = utils.CommitAttribute(i, attr, publicKey.H, &blinding)
+        if err != nil {
+            return models.AttributeSignature{}, err
+        }
+        openings[i] = &blinding
+    }
+
+    // Bind the commitments into the challenge
+    extend := func(t *utils.Transcript, rX *e.Scalar) {
+        for _, commitment := range commitments {
+            t.AppendG1("attribute", commitment)
+        }
+    }
+
+    signature, err := signWithExtension(publicKey, userPrivateKey, utils.DigestMessages(nil), extend, opts)
+    if err != nil {
+        return models.AttributeSignature{}, err
+    }
+    return models.AttributeSignature{Signature: signature, Commitments: commitments, Openings: openings}, nil
+}
@@ -0,0 +1,44 @@
+package sign
+
+import (
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// SignLinkable generates a BBS signature with a linkability tag tag = H(epoch)^x_i.
+// Signatures by the same member in the same epoch share the tag and can be linked
+// without opening them, e.g. for anonymous rate-limiting. The tag is bound into the challenge
+// together with R6 = H(epoch)^rX, proving it was computed with the same x_i as the signature.
+//
+// Parameters:
+//   - publicKey: The public key of the system.
+//   - userPrivateKey: The private key of the user signing the message.
+//   - m: The message to be signed.
+//   - epoch: The epoch within which signatures of the same member are linkable.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - models.LinkableSignature: The generated signature and its tag.
+//   - error: An error if the signing process fails.
+func SignLinkable(publicKey models.PublicKey, userPrivateKey models.User, m string, epoch []byte, opts ...utils.HashOption) (models.LinkableSignature, error) {
+    // Compute the tag H(epoch)^x_i
+    base := utils.LinkBase(epoch)
+    tag := new(e.G1)
+    tag.ScalarMult(&userPrivateKey.X, base)
+
+    // Bind the epoch, the tag and R6 = H(epoch)^rX into the challenge
+    extend := func(t *utils.Transcript, rX *e.Scalar) {
+        R6 := new(e.G1)
+        R6.ScalarMult(rX, base)
+        t.AppendMessage("epoch", epoch)
+        t.AppendG1("tag", tag)
+        t.AppendG1("R6", R6)
+    }
+
+    signature, err := signWithExtension(publicKey, userPrivateKey, utils.DigestMessages([]string{m}), extend, opts)
+    if err != nil {
+        return models.LinkableSignature{}, err
+    }
+    return models.LinkableSignature{Signature: signature, Tag: tag}, nil
+}
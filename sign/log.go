@@ -0,0 +1,51 @@
+package sign
+
+import (
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// LogSigner signs checkpoints of an append-only log.
+// It keeps only the running hash of the entries, so a checkpoint signs every entry appended
+// so far, in order, and verifies with a verify.LogVerifier fed the same entries.
+// A LogSigner is not safe for concurrent use.
+type LogSigner struct {
+    digest *utils.LogDigest
+}
+
+// NewLogSigner creates a LogSigner for an empty log.
+//
+// Returns:
+//   - *LogSigner: The log signer.
+func NewLogSigner() *LogSigner {
+    return &LogSigner{digest: utils.NewLogDigest()}
+}
+
+// Append adds an entry to the log.
+//
+// Parameters:
+//   - entry: The entry to be appended.
+func (l *LogSigner) Append(entry []byte) {
+    l.digest.Append(entry)
+}
+
+// SignCheckpoint generates a BBS signature over all entries appended so far.
+// Further entries can be appended afterwards and covered by a later checkpoint.
+//
+// Parameters:
+//   - publicKey: The public key of the system.
+//   - userPrivateKey: The private key of the user signing the checkpoint.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - models.Signature: The generated signature.
+//   - []byte: The checkpoint digest that was signed.
+//   - error: An error if the signing process fails.
+func (l *LogSigner) SignCheckpoint(publicKey models.PublicKey, userPrivateKey models.User, opts ...utils.HashOption) (models.Signature, []byte, error) {
+    digest := l.digest.Sum()
+    signature, err := signDigest(publicKey, userPrivateKey, digest, opts)
+    if err != nil {
+        return models.Signature{}, nil, err
+    }
+    return signature, digest[:], nil
+}
@@ -0,0 +1,117 @@
+package sign
+
+import (
+    "sync"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// PreparedSigner signs repeatedly for one user under one public key.
+// It keeps alpha, beta, the deltas and the r values in scratch space owned by the signer and zeroes
+// all of it before Sign returns, on success and on error. The T and R values are computed with the
+// same code as Sign (ComputeTValues, ComputeRValues and internal/proof), so the two cannot drift.
+// It caches no per-signature randomness: every value is drawn afresh on every call, and every element
+// placed in the returned signature is newly allocated, so signatures never alias.
+// A PreparedSigner is safe for concurrent use; calls to Sign are serialized.
+type PreparedSigner struct {
+    publicKey models.PublicKey
+    user      models.User
+
+    mu sync.Mutex
+
+    // random holds alpha, beta, rAlpha, rBeta, rX, rDelta1 and rDelta2 for the current call.
+    random [7]e.Scalar
+
+    delta1, delta2 e.Scalar
+}
+
+// NewPreparedSigner creates a PreparedSigner for the given user.
+//
+// Parameters:
+//   - publicKey: The group public key.
+//   - userPrivateKey: The user's private key (A_i, x_i).
+//
+// Returns:
+//   - *PreparedSigner: The prepared signer.
+func NewPreparedSigner(publicKey models.PublicKey, userPrivateKey models.User) *PreparedSigner {
+    return &PreparedSigner{
+        publicKey: publicKey,
+        user:      userPrivateKey,
+    }
+}
+
+// Sign generates a BBS signature over the message m.
+// It produces the same kind of signature as Sign and verifies with verify.Verify.
+//
+// Parameters:
+//   - m: The message to be signed.
+//   - opts: Options selecting the hash function used for the challenge.
+//
+// Returns:
+//   - models.Signature: The generated signature.
+//   - error: An error if the signing process fails.
+func (p *PreparedSigner) Sign(m string, opts ...utils.HashOption) (models.Signature, error) {
+    digest := utils.DigestMessages([]string{m})
+
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    defer p.wipe()
+
+    // Step 1: Draw alpha, beta and the randomizers of the R values
+    for i := range p.random {
+        scalar, err := utils.RandomScalar()
+        if err != nil {
+            return models.Signature{}, err
+        }
+        p.random[i] = scalar
+    }
+    alpha, beta := p.random[0], p.random[1]
+    rAlpha, rBeta, rX, rDelta1, rDelta2 := p.random[2], p.random[3], p.random[4], p.random[5], p.random[6]
+    pk, xI := &p.publicKey, p.user.X
+
+    // Step 2: Compute delta1 = alpha * x_i and delta2 = beta * x_i
+    p.delta1.Mul(&alpha, &xI)
+    p.delta2.Mul(&beta, &xI)
+
+    // Step 3: Compute T values
+    T1, T2, T3 := ComputeTValues(alpha, beta, pk.H, pk.U, pk.V, p.user.A)
+
+    // Step 4: Compute R values
+    R1, R2, R3, R4, R5, err := ComputeRValues(rAlpha, rBeta, rX, rDelta1, rDelta2, T1, T2, T3, pk.H, pk.U, pk.V, pk.W, pk.G2)
+    if err != nil {
+        return models.Signature{}, err
+    }
+
+    // Step 5: Compute challenge scalar c
+    c, err := utils.SignatureTranscript(digest[:], T1, T2, T3, R1, R2, R3, R4, R5, opts...).Challenge()
+    if err != nil {
+        return models.Signature{}, err
+    }
+
+    // Step 6: Compute s values
+    sAlpha, sBeta, sX, sDelta1, sDelta2 := ComputeSValues(alpha, beta, xI, &p.delta1, &p.delta2, rAlpha, rBeta, rX, rDelta1, rDelta2, c)
+
+    // Step 7: Construct the signature
+    return models.Signature{
+        T1:      T1,
+        T2:      T2,
+        T3:      T3,
+        C:       c,
+        SAlpha:  sAlpha,
+        SBeta:   sBeta,
+        SX:      sX,
+        SDelta1: sDelta1,
+        SDelta2: sDelta2,
+    }, nil
+}
+
+// wipe zeroes every per-signature secret held in scratch space.
+func (p *PreparedSigner) wipe() {
+    for i := range p.random {
+        p.random[i].SetUint64(0)
+    }
+    p.delta1.SetUint64(0)
+    p.delta2.SetUint64(0)
+}
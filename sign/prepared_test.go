@@ -0,0 +1,93 @@
+package sign
+
+import (
+    "crypto/sha256"
+    "errors"
+    "hash"
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestPreparedSigner tests that PreparedSigner produces fresh signatures that pass the self-check.
+func TestPreparedSigner(t *testing.T) {
+    result, err := keygen.KeyGen(2)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    signer := NewPreparedSigner(result.PublicKey, result.Users[1])
+    message := "Hello, world!"
+    first, err := signer.Sign(message)
+    assert.NoError(t, err, "PreparedSigner.Sign should not return an error")
+    second, err := signer.Sign(message)
+    assert.NoError(t, err, "PreparedSigner.Sign should not return an error")
+
+    assert.NoError(t, selfCheck(result.PublicKey, message, first), "The first signature should verify")
+    assert.NoError(t, selfCheck(result.PublicKey, message, second), "The second signature should verify")
+    assert.False(t, first.T1.IsEqual(second.T1), "Each signature should use fresh randomness")
+    assert.NotSame(t, first.T3, second.T3, "Signatures should not share elements")
+    assert.NotSame(t, first.SX, second.SX, "Signatures should not share scalars")
+}
+
+// failingHash is a hash function whose Write always fails.
+type failingHash struct {
+    hash.Hash
+}
+
+func (failingHash) Write(p []byte) (int, error) {
+    return 0, errors.New("hash unavailable")
+}
+
+// assertWiped asserts that no per-signature secret is left in the scratch space of the signer.
+func assertWiped(t *testing.T, signer *PreparedSigner) {
+    for i := range signer.random {
+        assert.Equal(t, 1, signer.random[i].IsZero(), "random[%d] should be zeroed", i)
+    }
+    assert.Equal(t, 1, signer.delta1.IsZero(), "delta1 should be zeroed")
+    assert.Equal(t, 1, signer.delta2.IsZero(), "delta2 should be zeroed")
+}
+
+// TestPreparedSignerWipe tests that PreparedSigner.Sign zeroes its scratch space on success and on error.
+func TestPreparedSignerWipe(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    signer := NewPreparedSigner(result.PublicKey, result.Users[0])
+
+    _, err = signer.Sign("Hello, world!")
+    assert.NoError(t, err, "PreparedSigner.Sign should not return an error")
+    assertWiped(t, signer)
+
+    // The challenge hash fails after every secret has been drawn
+    failing := utils.WithHash(func() hash.Hash { return failingHash{sha256.New()} })
+    _, err = signer.Sign("Hello, world!", failing)
+    assert.ErrorIs(t, err, utils.ErrHashFailure, "PreparedSigner.Sign should wrap ErrHashFailure")
+    assertWiped(t, signer)
+}
+
+// BenchmarkSign compares the allocations of Sign with PreparedSigner.Sign. Run it with -benchmem.
+func BenchmarkSign(b *testing.B) {
+    result, err := keygen.KeyGen(1)
+    if err != nil {
+        b.Fatal(err)
+    }
+    signer := NewPreparedSigner(result.PublicKey, result.Users[0])
+
+    for _, bench := range []struct {
+        name string
+        sign func(string) (models.Signature, error)
+    }{
+        {"Sign", func(m string) (models.Signature, error) { return Sign(result.PublicKey, result.Users[0], m) }},
+        {"PreparedSigner", func(m string) (models.Signature, error) { return signer.Sign(m) }},
+    } {
+        b.Run(bench.name, func(b *testing.B) {
+            b.ReportAllocs()
+            for i := 0; i < b.N; i++ {
+                if _, err := bench.sign("Hello, world!"); err != nil {
+                    b.Fatal(err)
+                }
+            }
+        })
+    }
+}
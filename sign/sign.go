@@ -5,6 +5,7 @@ package sign
 
 import (
     e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/internal/proof"
     "github.com/aniagut/msc-bbs/utils"
     "github.com/aniagut/msc-bbs/models"
 )
@@ -15,11 +16,83 @@ import (
 //   - publicKey: The public key of the system.
 //   - userPrivateKey: The private key of the user signing the message.
 //   - m: The message to be signed.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
 //
 // Returns:
 //   - models.Signature: The generated signature.
 //   - error: An error if the signing process fails.
-func Sign(publicKey models.PublicKey, userPrivateKey models.User, m string) (models.Signature, error) {
+func Sign(publicKey models.PublicKey, userPrivateKey models.User, m string, opts ...utils.HashOption) (models.Signature, error) {
+    return signDigest(publicKey, userPrivateKey, utils.DigestMessages([]string{m}), opts)
+}
+
+// SignVector generates a BBS signature for a vector of messages, e.g. the fields of a structured record.
+// Each message is length-prefixed and folded into the challenge in the given order,
+// so reordering the messages produces a different signature.
+// A single-element vector is signed exactly like the same message passed to Sign.
+//
+// Parameters:
+//   - publicKey: The public key of the system.
+//   - userPrivateKey: The private key of the user signing the messages.
+//   - msgs: The messages to be signed.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - models.Signature: The generated signature.
+//   - error: An error if the vector is empty or the signing process fails.
+func SignVector(publicKey models.PublicKey, userPrivateKey models.User, msgs []string, opts ...utils.HashOption) (models.Signature, error) {
+    if len(msgs) == 0 {
+        return models.Signature{}, utils.ErrEmptyMessageVector
+    }
+    return signDigest(publicKey, userPrivateKey, utils.DigestMessages(msgs), opts)
+}
+
+// SignRecord generates a BBS signature over a record of named fields, e.g. a flat JSON object.
+// The record is encoded with utils.CanonicalizeRecord, so the signature does not depend on the
+// iteration order of the map and verifies with VerifyRecord for any map with the same entries.
+//
+// Parameters:
+//   - publicKey: The public key of the system.
+//   - userPrivateKey: The private key of the user signing the record.
+//   - record: The record to be signed.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - models.Signature: The generated signature.
+//   - error: An error if the signing process fails.
+func SignRecord(publicKey models.PublicKey, userPrivateKey models.User, record map[string]string, opts ...utils.HashOption) (models.Signature, error) {
+    return signDigest(publicKey, userPrivateKey, utils.DigestRecord(record), opts)
+}
+
+// SignPrehashed generates a BBS signature for a message digest computed outside of this library,
+// e.g. for detached signatures over externally hashed content. The digest is bound into the
+// challenge as-is. Sign(m) signs utils.DigestMessages([]string{m}), so both functions agree
+// when the caller computes the digest the same way.
+//
+// Parameters:
+//   - publicKey: The public key of the system.
+//   - userPrivateKey: The private key of the user signing the digest.
+//   - digest: The 32-byte message digest to be signed.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - models.Signature: The generated signature.
+//   - error: An error if the signing process fails.
+func SignPrehashed(publicKey models.PublicKey, userPrivateKey models.User, digest [32]byte, opts ...utils.HashOption) (models.Signature, error) {
+    return signDigest(publicKey, userPrivateKey, digest, opts)
+}
+
+// signDigest generates a BBS signature over the message digest.
+func signDigest(publicKey models.PublicKey, userPrivateKey models.User, digest [32]byte, opts []utils.HashOption) (models.Signature, error) {
+    return signWithExtension(publicKey, userPrivateKey, digest, nil, opts)
+}
+
+// transcriptExtension binds additional statements about x_i into the challenge transcript.
+// It receives the randomness rX used for x_i, so the statements share the response sX.
+type transcriptExtension func(t *utils.Transcript, rX *e.Scalar)
+
+// signWithExtension generates a BBS signature over the message digest,
+// letting extend append further values to the challenge transcript if it is not nil.
+func signWithExtension(publicKey models.PublicKey, userPrivateKey models.User, digest [32]byte, extend transcriptExtension, opts []utils.HashOption) (models.Signature, error) {
     // Step 1: Generate random scalars alpha and beta
     alpha, err := utils.RandomScalar()
     if err != nil {
@@ -44,20 +117,17 @@ func Sign(publicKey models.PublicKey, userPrivateKey models.User, m string) (mod
     T1, T2, T3 := ComputeTValues(alpha, beta, publicKey.H, publicKey.U, publicKey.V, userPrivateKey.A)
 
     // Step 5: Compute R values
-    R1, R2, R3, R4, R5 := ComputeRValues(rAlpha, rBeta, rX, rDelta1, rDelta2, T1, T2, T3, publicKey.H, publicKey.U, publicKey.V, publicKey.W, publicKey.G2)
+    R1, R2, R3, R4, R5, err := ComputeRValues(rAlpha, rBeta, rX, rDelta1, rDelta2, T1, T2, T3, publicKey.H, publicKey.U, publicKey.V, publicKey.W, publicKey.G2)
+    if err != nil {
+        return models.Signature{}, err
+    }
 
     // Step 6: Compute challenge scalar c
-    c, err := utils.HashToScalar(
-        utils.SerializeString(m),
-        utils.SerializeG1(T1),
-        utils.SerializeG1(T2),
-        utils.SerializeG1(T3),
-        utils.SerializeG1(R1),
-        utils.SerializeG1(R2),
-        utils.SerializeGt(R3),
-        utils.SerializeG1(R4),
-        utils.SerializeG1(R5),
-    )
+    transcript := utils.SignatureTranscript(digest[:], T1, T2, T3, R1, R2, R3, R4, R5, opts...)
+    if extend != nil {
+        extend(transcript, &rX)
+    }
+    c, err := transcript.Challenge()
     if err != nil {
         return models.Signature{}, err
     }
@@ -116,70 +186,36 @@ func ComputeT3(alpha, beta e.Scalar, h, aI *e.G1) *e.G1 {
 // ComputeRValues computes the R1, R2, R3, R4, and R5 values for the signature.
 // R1 = u^rAlpha, R2 = v^rBeta, R3 = e(T3^(rX), g2) * e(h^-(rAlpha + rBeta), w) * e(h^-(rDelta1 + rDelta2),
 // R4 = T1^rX * u^(-rDelta1), R5 = T2^rX * v^(-rDelta2).
-func ComputeRValues(rAlpha, rBeta, rX, rDelta1, rDelta2 e.Scalar, T1, T2, T3, h, u, v *e.G1, w, g2 *e.G2) (*e.G1, *e.G1, *e.Gt, *e.G1, *e.G1) {
-    R1 := new(e.G1)
-    R1.ScalarMult(&rAlpha, u)
+func ComputeRValues(rAlpha, rBeta, rX, rDelta1, rDelta2 e.Scalar, T1, T2, T3, h, u, v *e.G1, w, g2 *e.G2) (*e.G1, *e.G1, *e.Gt, *e.G1, *e.G1, error) {
+    R1 := proof.R1(u, &rAlpha)
 
-    R2 := new(e.G1)
-    R2.ScalarMult(&rBeta, v)
+    R2 := proof.R2(v, &rBeta)
 
-    R3 := ComputeR3(T3, g2, h, w, rX, rAlpha, rBeta, rDelta1, rDelta2)
+    R3, err := ComputeR3(T3, g2, h, w, rX, rAlpha, rBeta, rDelta1, rDelta2)
+    if err != nil {
+        return nil, nil, nil, nil, nil, err
+    }
 
     R4 := ComputeR4(T1, u, rX, rDelta1)
 
     R5 := ComputeR5(T2, v, rX, rDelta2)
 
-    return R1, R2, R3, R4, R5
+    return R1, R2, R3, R4, R5, nil
 }
 
 // ComputeR3 computes R3 = e(T3^(rX), g2) * e(h^-(rAlpha + rBeta), w) * e(h^-(rDelta1 + rDelta2), g2).
-func ComputeR3(T3 *e.G1, g2 *e.G2, h *e.G1, w *e.G2, rX, rAlpha, rBeta, rDelta1, rDelta2 e.Scalar) *e.Gt {
-    rAlphaBeta := new(e.Scalar)
-    rAlphaBeta.Add(&rAlpha, &rBeta)
-    rAlphaBeta.Neg()
-
-    rDelta := new(e.Scalar)
-    rDelta.Add(&rDelta1, &rDelta2)
-    rDelta.Neg()
-
-    R3 := e.ProdPair(
-        []*e.G1{T3, h, h},
-        []*e.G2{g2, w, g2},
-        []*e.Scalar{&rX, rAlphaBeta, rDelta},
-    )
-    return R3
+func ComputeR3(T3 *e.G1, g2 *e.G2, h *e.G1, w *e.G2, rX, rAlpha, rBeta, rDelta1, rDelta2 e.Scalar) (*e.Gt, error) {
+    return proof.R3(T3, h, g2, w, &rX, &rAlpha, &rBeta, &rDelta1, &rDelta2)
 }
 
 // ComputeR4 computes R4 = T1^(rX) * u^(-rDelta1).
 func ComputeR4(T1, u *e.G1, rX, rDelta1 e.Scalar) *e.G1 {
-    T1rX := new(e.G1)
-    T1rX.ScalarMult(&rX, T1)
-
-    minusRDelta1 := rDelta1
-    minusRDelta1.Neg()
-    uRDelta1 := new(e.G1)
-    uRDelta1.ScalarMult(&minusRDelta1, u)
-    
-    R4 := new(e.G1)
-    R4.Add(T1rX, uRDelta1)
-    
-    return R4
+    return proof.R4(T1, u, &rX, &rDelta1)
 }
 
 // ComputeR5 computes R5 = T2^(rX) * v^(-rDelta2).
 func ComputeR5(T2, v *e.G1, rX, rDelta2 e.Scalar) *e.G1 {
-    T2rX := new(e.G1)
-    T2rX.ScalarMult(&rX, T2)
-
-    minusRDelta2 := rDelta2
-    minusRDelta2.Neg()
-    vRDelta2 := new(e.G1)
-    vRDelta2.ScalarMult(&minusRDelta2, v)
-    
-    R5 := new(e.G1)
-    R5.Add(T2rX, vRDelta2)
-    
-    return R5
+    return proof.R5(T2, v, &rX, &rDelta2)
 }
 
 // ComputeSValues computes the s values for the signature.
@@ -0,0 +1,48 @@
+package sign
+
+import (
+    "errors"
+    "fmt"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+    "github.com/aniagut/msc-bbs/verify"
+)
+
+// ErrSelfCheckFailed is returned by SignVerified when the generated signature does not verify.
+var ErrSelfCheckFailed = errors.New("signature self-check failed")
+
+// SignVerified generates a BBS signature for a given message and verifies it before returning,
+// catching faults or latent bugs that would produce a non-verifying signature.
+// The self-check roughly doubles the cost of signing, so it is opt-in.
+//
+// Parameters:
+//   - publicKey: The public key of the system.
+//   - userPrivateKey: The private key of the user signing the message.
+//   - m: The message to be signed.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - models.Signature: The generated and verified signature.
+//   - error: An error if the signing process fails, or ErrSelfCheckFailed if the signature does not verify.
+func SignVerified(publicKey models.PublicKey, userPrivateKey models.User, m string, opts ...utils.HashOption) (models.Signature, error) {
+    signature, err := Sign(publicKey, userPrivateKey, m, opts...)
+    if err != nil {
+        return models.Signature{}, err
+    }
+    if err := selfCheck(publicKey, m, signature, opts...); err != nil {
+        return models.Signature{}, err
+    }
+    return signature, nil
+}
+
+// selfCheck verifies a freshly generated signature and returns ErrSelfCheckFailed if it is not valid.
+func selfCheck(publicKey models.PublicKey, m string, signature models.Signature, opts ...utils.HashOption) error {
+    valid, err := verify.Verify(publicKey, m, signature, opts...)
+    if err != nil {
+        return fmt.Errorf("%w: %v", ErrSelfCheckFailed, err)
+    }
+    if !valid {
+        return ErrSelfCheckFailed
+    }
+    return nil
+}
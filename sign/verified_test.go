@@ -0,0 +1,39 @@
+package sign
+
+import (
+    "testing"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestSignVerified tests that SignVerified returns a signature for normal input.
+func TestSignVerified(t *testing.T) {
+    result, err := keygen.KeyGen(2)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    signature, err := SignVerified(result.PublicKey, result.Users[0], "Hello, world!")
+    assert.NoError(t, err, "SignVerified should not return an error")
+    assert.NotNil(t, signature.T1, "T1 should not be nil")
+}
+
+// TestSelfCheckFault tests that the self-check detects a corrupted signature.
+func TestSelfCheckFault(t *testing.T) {
+    result, err := keygen.KeyGen(2)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    message := "Hello, world!"
+    signature, err := Sign(result.PublicKey, result.Users[0], message)
+    assert.NoError(t, err, "Sign should not return an error")
+    assert.NoError(t, selfCheck(result.PublicKey, message, signature), "The self-check should pass for a valid signature")
+
+    // Corrupt sX after signing
+    corrupted := new(e.Scalar)
+    corrupted.SetUint64(1)
+    corrupted.Add(corrupted, signature.SX)
+    signature.SX = corrupted
+
+    err = selfCheck(result.PublicKey, message, signature)
+    assert.ErrorIs(t, err, ErrSelfCheckFailed, "The self-check should fail for a corrupted signature")
+}
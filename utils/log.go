@@ -0,0 +1,47 @@
+package utils
+
+import (
+    "crypto/sha256"
+    "encoding/binary"
+)
+
+// logDomain is the domain separation label of the running hash of an append-only log.
+const logDomain = "BBS-LOG"
+
+// LogDigest maintains the running hash of an append-only log of entries.
+// Each entry is chained as state = SHA-256(state || len(entry) || entry), starting from
+// SHA-256(logDomain), so the digest commits to every entry and to their order, and a log
+// can be hashed incrementally without keeping its entries.
+// A LogDigest is not safe for concurrent use.
+type LogDigest struct {
+    state   [32]byte
+    entries uint64
+}
+
+// NewLogDigest creates the running hash of an empty log.
+func NewLogDigest() *LogDigest {
+    return &LogDigest{state: sha256.Sum256([]byte(logDomain))}
+}
+
+// Append adds an entry to the log.
+func (d *LogDigest) Append(entry []byte) {
+    var length [8]byte
+    binary.BigEndian.PutUint64(length[:], uint64(len(entry)))
+
+    h := sha256.New()
+    h.Write(d.state[:])
+    h.Write(length[:])
+    h.Write(entry)
+    h.Sum(d.state[:0])
+    d.entries++
+}
+
+// Len returns the number of entries appended so far.
+func (d *LogDigest) Len() uint64 {
+    return d.entries
+}
+
+// Sum returns the digest of all entries appended so far.
+func (d *LogDigest) Sum() [32]byte {
+    return d.state
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+    "testing"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestDoubleScalarMult tests that DoubleScalarMult matches two ScalarMult calls and an Add.
+func TestDoubleScalarMult(t *testing.T) {
+    P := e.G1Generator()
+    Q, err := RandomG1Element()
+    assert.NoError(t, err, "RandomG1Element should not return an error")
+
+    var zero, one, minusOne e.Scalar
+    one.SetOne()
+    minusOne.SetOne()
+    minusOne.Neg()
+    k1, err := RandomScalar()
+    assert.NoError(t, err, "RandomScalar should not return an error")
+    k2, err := RandomScalar()
+    assert.NoError(t, err, "RandomScalar should not return an error")
+
+    cases := [][2]e.Scalar{{k1, k2}, {zero, zero}, {one, zero}, {zero, minusOne}, {minusOne, minusOne}}
+    for _, c := range cases {
+        expected := new(e.G1)
+        expected.ScalarMult(&c[0], P)
+        term := new(e.G1)
+        term.ScalarMult(&c[1], &Q)
+        expected.Add(expected, term)
+
+        result := DoubleScalarMult(&c[0], P, &c[1], &Q)
+        assert.True(t, expected.IsEqual(result), "DoubleScalarMult should match k1 * P + k2 * Q")
+    }
+}
@@ -0,0 +1,117 @@
+package utils
+
+import (
+    "fmt"
+    "sync/atomic"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// MultiPair computes the product of pairings e(g1s[i], g2s[i])^scalars[i] with ProdPair.
+// It checks that the three slices have the same length and contain no nil entries,
+// so an out-of-sync input is reported as an error instead of reaching ProdPair.
+// Terms with an identity argument or a zero exponent are left out, since ProdPair would return 1 for the whole product.
+//
+// Parameters:
+//   - g1s: The G1 arguments of the pairings.
+//   - g2s: The G2 arguments of the pairings.
+//   - scalars: The exponents applied to each pairing.
+//
+// Returns:
+//   - *e.Gt: The product of the pairings.
+//   - error: ErrLengthMismatch if the slices differ in length, or ErrNilElement if an entry is nil.
+func MultiPair(g1s []*e.G1, g2s []*e.G2, scalars []*e.Scalar) (*e.Gt, error) {
+    if len(g1s) != len(g2s) || len(g1s) != len(scalars) {
+        return nil, fmt.Errorf("%w: %d G1, %d G2 and %d scalar inputs", ErrLengthMismatch, len(g1s), len(g2s), len(scalars))
+    }
+    for i := range g1s {
+        if g1s[i] == nil || g2s[i] == nil || scalars[i] == nil {
+            return nil, fmt.Errorf("%w: pairing input %d", ErrNilElement, i)
+        }
+    }
+
+    // ProdPair returns the identity for the whole product if any input is the identity or any
+    // exponent is zero, so such terms, which contribute e(O, Q) = e(P, Q)^0 = 1, are left out instead
+    for i := range g1s {
+        if trivialTerm(g1s[i], g2s[i], scalars[i]) {
+            return multiPairSkippingTrivial(g1s, g2s, scalars), nil
+        }
+    }
+    Pairings.record(len(g1s))
+    return e.ProdPair(g1s, g2s, scalars), nil
+}
+
+// trivialTerm reports whether the pairing term e(P, Q)^k is 1 because P or Q is the identity or k is zero.
+func trivialTerm(P *e.G1, Q *e.G2, k *e.Scalar) bool {
+    return P.IsIdentity() || Q.IsIdentity() || k.IsZero() == 1
+}
+
+// multiPairSkippingTrivial computes the product of pairings without the terms that are trivially 1.
+// It copies the remaining terms, so the caller's slices are left untouched.
+func multiPairSkippingTrivial(g1s []*e.G1, g2s []*e.G2, scalars []*e.Scalar) *e.Gt {
+    var keptG1s []*e.G1
+    var keptG2s []*e.G2
+    var keptScalars []*e.Scalar
+    for i := range g1s {
+        if trivialTerm(g1s[i], g2s[i], scalars[i]) {
+            continue
+        }
+        keptG1s = append(keptG1s, g1s[i])
+        keptG2s = append(keptG2s, g2s[i])
+        keptScalars = append(keptScalars, scalars[i])
+    }
+    if len(keptG1s) == 0 {
+        result := new(e.Gt)
+        result.SetIdentity()
+        return result
+    }
+    Pairings.record(len(keptG1s))
+    return e.ProdPair(keptG1s, keptG2s, keptScalars)
+}
+
+// PairingCounter counts the pairings computed by MultiPair while it is enabled.
+// ProdPair evaluates one Miller loop per input pair but a single final exponentiation,
+// so both numbers are tracked to show what batching the pairings saves.
+type PairingCounter struct {
+    enabled              atomic.Bool
+    millerLoops          atomic.Int64
+    finalExponentiations atomic.Int64
+}
+
+// Pairings is the counter used by MultiPair. It is disabled by default, so library users pay nothing for it.
+var Pairings PairingCounter
+
+// Enable starts counting pairings.
+func (c *PairingCounter) Enable() {
+    c.enabled.Store(true)
+}
+
+// Disable stops counting pairings. The counts recorded so far are kept.
+func (c *PairingCounter) Disable() {
+    c.enabled.Store(false)
+}
+
+// Reset sets both counts to zero.
+func (c *PairingCounter) Reset() {
+    c.millerLoops.Store(0)
+    c.finalExponentiations.Store(0)
+}
+
+// MillerLoops returns the number of pairings (Miller loops) recorded since the last Reset.
+func (c *PairingCounter) MillerLoops() int64 {
+    return c.millerLoops.Load()
+}
+
+// FinalExponentiations returns the number of final exponentiations recorded since the last Reset.
+func (c *PairingCounter) FinalExponentiations() int64 {
+    return c.finalExponentiations.Load()
+}
+
+// record adds a product of n pairings to the counts if counting is enabled.
+func (c *PairingCounter) record(n int) {
+    if !c.enabled.Load() {
+        return
+    }
+    c.millerLoops.Add(int64(n))
+    c.finalExponentiations.Add(1)
+}
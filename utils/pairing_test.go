@@ -0,0 +1,51 @@
+package utils
+
+import (
+    "testing"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestMultiPair tests the MultiPair function.
+func TestMultiPair(t *testing.T) {
+    g1 := e.G1Generator()
+    g2 := e.G2Generator()
+    var two e.Scalar
+    two.SetUint64(2)
+
+    // e(g1, g2)^2 computed by MultiPair matches the product of two pairings
+    result, err := MultiPair([]*e.G1{g1}, []*e.G2{g2}, []*e.Scalar{&two})
+    assert.NoError(t, err, "MultiPair should not return an error")
+    expected := e.Pair(g1, g2)
+    expected.Mul(expected, e.Pair(g1, g2))
+    assert.True(t, result.IsEqual(expected), "MultiPair should match the product of the pairings")
+
+    // Mismatched lengths are rejected
+    _, err = MultiPair([]*e.G1{g1, g1}, []*e.G2{g2}, []*e.Scalar{&two})
+    assert.ErrorIs(t, err, ErrLengthMismatch, "MultiPair should reject mismatched lengths")
+    _, err = MultiPair([]*e.G1{g1}, []*e.G2{g2}, []*e.Scalar{})
+    assert.ErrorIs(t, err, ErrLengthMismatch, "MultiPair should reject mismatched lengths")
+
+    // Nil entries are rejected
+    _, err = MultiPair([]*e.G1{nil}, []*e.G2{g2}, []*e.Scalar{&two})
+    assert.ErrorIs(t, err, ErrNilElement, "MultiPair should reject nil entries")
+
+    // A term with an identity argument contributes 1 instead of collapsing the product
+    var identity e.G1
+    identity.SetIdentity()
+    g1s := []*e.G1{&identity, g1}
+    result, err = MultiPair(g1s, []*e.G2{g2, g2}, []*e.Scalar{&two, &two})
+    assert.NoError(t, err, "MultiPair should not return an error")
+    assert.True(t, result.IsEqual(expected), "An identity term should contribute 1")
+    assert.Same(t, &identity, g1s[0], "MultiPair should not modify the caller's slices")
+    result, err = MultiPair([]*e.G1{&identity}, []*e.G2{g2}, []*e.Scalar{&two})
+    assert.NoError(t, err, "MultiPair should not return an error")
+    assert.True(t, result.IsIdentity(), "A product of identity terms should be 1")
+
+    // A term with a zero exponent contributes 1 instead of collapsing the product
+    var zero e.Scalar
+    result, err = MultiPair([]*e.G1{g1, g1}, []*e.G2{g2, g2}, []*e.Scalar{&zero, &two})
+    assert.NoError(t, err, "MultiPair should not return an error")
+    assert.True(t, result.IsEqual(expected), "A zero exponent should contribute 1")
+}
@@ -0,0 +1,117 @@
+package utils
+
+import (
+    "encoding/binary"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// Element type tags used to domain-separate the transcript entries.
+const (
+    tagMessage byte = iota + 1
+    tagG1
+    tagG2
+    tagGt
+    tagScalar
+)
+
+// SignatureDomain is the domain separation label of the BBS signature challenge.
+const SignatureDomain = "BBS-GROUP-SIGNATURE"
+
+// Transcript accumulates the public values of a Fiat–Shamir proof in a fixed, unambiguous encoding.
+// Every entry is tagged with its type and a label, and both the label and the value
+// are length-prefixed, so two different sequences of entries never produce the same bytes.
+type Transcript struct {
+    data   []byte
+    config HashConfig
+}
+
+// NewTranscript creates a transcript bound to the given domain separation label.
+// The options select the hash function used by Challenge.
+func NewTranscript(domain string, opts ...HashOption) *Transcript {
+    t := &Transcript{config: NewHashConfig(opts...)}
+    t.append(tagMessage, "domain", []byte(domain))
+    return t
+}
+
+// AppendMessage appends serialized message bytes to the transcript.
+func (t *Transcript) AppendMessage(label string, m []byte) {
+    t.append(tagMessage, label, m)
+}
+
+// AppendG1 appends a G1 element to the transcript.
+func (t *Transcript) AppendG1(label string, g *e.G1) {
+    t.append(tagG1, label, SerializeG1(g))
+}
+
+// AppendG2 appends a G2 element to the transcript.
+func (t *Transcript) AppendG2(label string, g *e.G2) {
+    t.append(tagG2, label, g.Bytes())
+}
+
+// AppendGt appends a Gt element to the transcript.
+func (t *Transcript) AppendGt(label string, g *e.Gt) {
+    t.append(tagGt, label, SerializeGt(g))
+}
+
+// AppendScalar appends a scalar to the transcript.
+func (t *Transcript) AppendScalar(label string, s *e.Scalar) {
+    data := SerializeScalar(s)
+    t.append(tagScalar, label, data[:])
+}
+
+// Bytes returns the encoded transcript.
+func (t *Transcript) Bytes() []byte {
+    return append([]byte(nil), t.data...)
+}
+
+// Challenge hashes the transcript into a scalar in Zp.
+func (t *Transcript) Challenge() (e.Scalar, error) {
+    return HashToScalarWith(t.config, t.data)
+}
+
+// append writes a tagged, length-prefixed entry to the transcript.
+func (t *Transcript) append(tag byte, label string, value []byte) {
+    var length [8]byte
+    t.data = append(t.data, tag)
+    binary.BigEndian.PutUint64(length[:], uint64(len(label)))
+    t.data = append(t.data, length[:]...)
+    t.data = append(t.data, label...)
+    binary.BigEndian.PutUint64(length[:], uint64(len(value)))
+    t.data = append(t.data, length[:]...)
+    t.data = append(t.data, value...)
+}
+
+// LabeledInput is an input to HashToScalarLabeled: a byte string together with the label naming its role.
+type LabeledInput struct {
+    Label string
+    Data  []byte
+}
+
+// HashToScalarLabeled hashes labeled inputs into a scalar in Zp using SHA-256.
+// Each label is hashed alongside its data with the transcript encoding, so the inputs are
+// self-describing: swapping two inputs with different labels changes the result even if their
+// bytes are identical, and no two different input lists share an encoding.
+func HashToScalarLabeled(pairs ...LabeledInput) (e.Scalar, error) {
+    t := &Transcript{config: NewHashConfig()}
+    for _, pair := range pairs {
+        t.append(tagMessage, pair.Label, pair.Data)
+    }
+    return t.Challenge()
+}
+
+// SignatureTranscript builds the challenge transcript of a BBS signature.
+// Sign and Verify both call it, so the challenge inputs are always assembled in the same order.
+func SignatureTranscript(message []byte, T1, T2, T3, R1, R2 *e.G1, R3 *e.Gt, R4, R5 *e.G1, opts ...HashOption) *Transcript {
+    t := NewTranscript(SignatureDomain, opts...)
+    t.AppendMessage("m", message)
+    t.AppendG1("T1", T1)
+    t.AppendG1("T2", T2)
+    t.AppendG1("T3", T3)
+    t.AppendG1("R1", R1)
+    t.AppendG1("R2", R2)
+    t.AppendGt("R3", R3)
+    t.AppendG1("R4", R4)
+    t.AppendG1("R5", R5)
+    return t
+}
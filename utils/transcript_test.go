@@ -0,0 +1,69 @@
+package utils
+
+import (
+    "testing"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestTranscript tests that the transcript encoding is unambiguous.
+func TestTranscript(t *testing.T) {
+    g := e.G1Generator()
+
+    // The same entries produce the same bytes and challenge
+    t1 := NewTranscript("test")
+    t1.AppendMessage("m", []byte("ab"))
+    t1.AppendG1("g", g)
+    t2 := NewTranscript("test")
+    t2.AppendMessage("m", []byte("ab"))
+    t2.AppendG1("g", g)
+    assert.Equal(t, t1.Bytes(), t2.Bytes(), "Equal transcripts should produce equal bytes")
+    c1, err := t1.Challenge()
+    assert.NoError(t, err, "Challenge should not return an error")
+    c2, err := t2.Challenge()
+    assert.NoError(t, err, "Challenge should not return an error")
+    assert.True(t, c1.IsEqual(&c2) == 1, "Equal transcripts should produce equal challenges")
+
+    // Moving bytes between entries changes the encoding
+    t3 := NewTranscript("test")
+    t3.AppendMessage("m", []byte("a"))
+    t3.AppendMessage("m", []byte("b"))
+    t4 := NewTranscript("test")
+    t4.AppendMessage("m", []byte("ab"))
+    assert.NotEqual(t, t3.Bytes(), t4.Bytes(), "Entries should be length-prefixed")
+
+    // Different domains produce different encodings
+    assert.NotEqual(t, NewTranscript("a").Bytes(), NewTranscript("b").Bytes(), "Domains should be separated")
+}
+
+// TestHashToScalarLabeled tests that the labels take part in the hash.
+func TestHashToScalarLabeled(t *testing.T) {
+    data := []byte("identical")
+
+    // Swapping two inputs with identical bytes but different labels changes the output
+    c1, err := HashToScalarLabeled(LabeledInput{"issuer", data}, LabeledInput{"subject", data})
+    assert.NoError(t, err, "HashToScalarLabeled should not return an error")
+    c2, err := HashToScalarLabeled(LabeledInput{"subject", data}, LabeledInput{"issuer", data})
+    assert.NoError(t, err, "HashToScalarLabeled should not return an error")
+    assert.True(t, c1.IsEqual(&c2) == 0, "Swapping labeled inputs should change the output")
+
+    // Unlabeled hashing cannot tell the two orders apart
+    h1, err := HashToScalar(data, data)
+    assert.NoError(t, err, "HashToScalar should not return an error")
+    h2, err := HashToScalar(data, data)
+    assert.NoError(t, err, "HashToScalar should not return an error")
+    assert.True(t, h1.IsEqual(&h2) == 1, "HashToScalar only sees the bytes")
+
+    // The same labeled inputs produce the same output
+    c3, err := HashToScalarLabeled(LabeledInput{"issuer", data}, LabeledInput{"subject", data})
+    assert.NoError(t, err, "HashToScalarLabeled should not return an error")
+    assert.True(t, c1.IsEqual(&c3) == 1, "HashToScalarLabeled should be deterministic")
+
+    // Moving bytes between inputs changes the output
+    c4, err := HashToScalarLabeled(LabeledInput{"m", []byte("ab")}, LabeledInput{"m", []byte("c")})
+    assert.NoError(t, err, "HashToScalarLabeled should not return an error")
+    c5, err := HashToScalarLabeled(LabeledInput{"m", []byte("a")}, LabeledInput{"m", []byte("bc")})
+    assert.NoError(t, err, "HashToScalarLabeled should not return an error")
+    assert.True(t, c4.IsEqual(&c5) == 0, "Inputs should be length-prefixed")
+}
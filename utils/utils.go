@@ -2,6 +2,20 @@
 // in the BBS signature scheme. It includes functions for generating random scalars,
 // hashing to scalars, and serializing elements of the elliptic curve groups.
 // The package uses the BLS12-381 elliptic curve for cryptographic operations.
+//
+// Scalars are always encoded big-endian, matching both big.Int.Bytes and circl's Scalar.MarshalBinary
+// and SetBytes. SerializeScalar and DeserializeScalar are the fixed-width conversions between the two
+// representations, and reduceToScalar is the only conversion of unbounded bytes; no code converts
+// scalars to bytes in any other way.
+//
+// No code selects between secret scalars, so the library has no constant-time selection helper.
+// The branches that do depend on secret or freshly drawn values only reject a value that is then
+// discarded, or report a degenerate input:
+//   - RandomScalarFromReader and randomBelow redraw zero or out-of-range candidates, which are never used.
+//   - keygen's checkRandomness compares throwaway scalars that are discarded afterwards.
+//   - keygen's drawEpsilons redraws epsilon2 when it equals epsilon1, which reveals only that a draw was rejected.
+//   - keygen's computeA rejects an identity A, which only reveals that gamma + x_i = 0.
+//   - KeyGenWithGamma rejects a zero gamma, which only reveals that the input was degenerate.
 package utils
 
 import (
@@ -9,40 +23,127 @@ import (
     "math/big"
     "crypto/sha256"
     "errors"
+    "fmt"
+    "encoding/binary"
+    "hash"
+    "io"
+    "sort"
 
     e "github.com/cloudflare/circl/ecc/bls12381"
 )
 
+var (
+    // ErrRandomnessFailure is returned when random values cannot be drawn from the source of randomness.
+    ErrRandomnessFailure = errors.New("randomness failure")
+    // ErrHashFailure is returned when inputs cannot be hashed.
+    ErrHashFailure = errors.New("hash failure")
+    // ErrEmptyMessageVector is returned when a vector of messages to sign or verify is empty.
+    ErrEmptyMessageVector = errors.New("message vector must not be empty")
+    // ErrNegativeScalar is returned when a negative integer is converted into a scalar.
+    ErrNegativeScalar = errors.New("scalar must not be negative")
+    // ErrInvalidScalarEncoding is returned when bytes do not encode a canonical scalar.
+    ErrInvalidScalarEncoding = errors.New("invalid scalar encoding")
+    // ErrNonCanonicalScalar is returned when an encoded scalar is not below the group order.
+    // It wraps ErrInvalidScalarEncoding.
+    ErrNonCanonicalScalar = fmt.Errorf("%w: value is not below the group order", ErrInvalidScalarEncoding)
+    // ErrNotInSubgroup is returned when a generated group element is not in the prime-order subgroup.
+    ErrNotInSubgroup = errors.New("element is not in the prime-order subgroup")
+    // ErrLengthMismatch is returned when parallel input slices have different lengths.
+    ErrLengthMismatch = errors.New("input slices must have equal length")
+    // ErrNilElement is returned when a required group element or scalar is nil.
+    ErrNilElement = errors.New("element must not be nil")
+)
+
+// maxZeroScalarRetries bounds how often RandomScalarFromReader redraws a zero scalar.
+// A healthy source produces zero with negligible probability, so repeated zeros indicate a broken reader.
+const maxZeroScalarRetries = 8
+
+// maxRejectionRetries bounds how often randomBelow redraws a candidate that is not below the bound.
+// For the group order a candidate is rejected with probability below 1/10, so 64 rejections in a row
+// indicate a broken reader, e.g. one returning only 0xFF bytes, on which crypto/rand.Int never returns.
+const maxRejectionRetries = 64
+
+// maxIdentityRetries bounds how often RandomG1ElementFromReader redraws an identity element.
+// Hashing to the curve yields the identity with negligible probability, like a zero scalar above.
+const maxIdentityRetries = 8
+
 // RandomScalar generates a random scalar in Zp* (the field of scalars modulo the curve order).
 func RandomScalar() (e.Scalar, error) {
+    return RandomScalarFromReader(rand.Reader)
+}
+
+// RandomScalarFromReader generates a random scalar in Zp* using the given source of randomness.
+func RandomScalarFromReader(random io.Reader) (e.Scalar, error) {
     order := OrderAsBigInt()
-    bigIntScalar, err := rand.Int(rand.Reader, order)
-    if err != nil {
-        return e.Scalar{}, errors.New("failed to generate random scalar")
+    for i := 0; i < maxZeroScalarRetries; i++ {
+        bigIntScalar, err := randomBelow(random, order)
+        if err != nil {
+            return e.Scalar{}, fmt.Errorf("%w: failed to generate random scalar: %v", ErrRandomnessFailure, err)
+        }
+
+        if bigIntScalar.Sign() == 0 { // Ensure it's nonzero
+            continue
+        }
+
+        // Convert to a scalar
+        return ScalarFromBigInt(bigIntScalar)
     }
+    return e.Scalar{}, fmt.Errorf("%w: failed to generate nonzero random scalar", ErrRandomnessFailure)
+}
 
-    if bigIntScalar.Sign() == 0 { // Ensure it's nonzero
-        return RandomScalar()
+// randomBelow draws a uniform integer in [0, max) like crypto/rand.Int, reading the same bytes from random,
+// but gives up after maxRejectionRetries rejected candidates instead of looping forever.
+func randomBelow(random io.Reader, max *big.Int) (*big.Int, error) {
+    // Read just enough bytes for max - 1 and clear the bits above its length
+    bitLen := new(big.Int).Sub(max, big.NewInt(1)).BitLen()
+    buf := make([]byte, (bitLen+7)/8)
+    topBits := uint(bitLen % 8)
+    if topBits == 0 {
+        topBits = 8
     }
 
-    // Convert to a scalar
-    var scalar e.Scalar
-    scalar.SetBytes(bigIntScalar.Bytes())
-    return scalar, nil
+    n := new(big.Int)
+    for i := 0; i < maxRejectionRetries; i++ {
+        if _, err := io.ReadFull(random, buf); err != nil {
+            return nil, err
+        }
+        buf[0] &= uint8(int(1<<topBits) - 1)
+        n.SetBytes(buf)
+        if n.Cmp(max) < 0 {
+            return n, nil
+        }
+    }
+    return nil, fmt.Errorf("%d candidates in a row were not below the bound", maxRejectionRetries)
 }
 
 // RandomG1Element generates a random element in the elliptic curve group G1.
 func RandomG1Element() (e.G1, error) {
+    return RandomG1ElementFromReader(rand.Reader)
+}
+
+// RandomG1ElementFromReader generates a random element in the elliptic curve group G1 using the given source of randomness.
+// The element is used as a base for discrete-log security, so an identity element is redrawn
+// and the result is checked to be in the prime-order subgroup.
+func RandomG1ElementFromReader(random io.Reader) (e.G1, error) {
     var h e.G1
     randomBytes := make([]byte, 48)
-    _, err := rand.Read(randomBytes)
-    if err != nil {
-        return e.G1{}, errors.New("failed to generate random input for hashing to G1")
-    }
+    for i := 0; i < maxIdentityRetries; i++ {
+        _, err := io.ReadFull(random, randomBytes)
+        if err != nil {
+            return e.G1{}, fmt.Errorf("%w: failed to generate random input for hashing to G1", ErrRandomnessFailure)
+        }
 
-    // Hash the random bytes to the curve using a domain separation tag
-    h.Hash(randomBytes, []byte("domain-separation-tag"))
-    return h, nil
+        // Hash the random bytes to the curve using a domain separation tag
+        h.Hash(randomBytes, []byte("domain-separation-tag"))
+        if h.IsIdentity() {
+            continue
+        }
+        if !h.IsOnG1() {
+            return e.G1{}, ErrNotInSubgroup
+        }
+        return h, nil
+    }
+    return e.G1{}, fmt.Errorf("%w: failed to generate non-identity element of G1", ErrRandomnessFailure)
 }
 
 // OrderAsBigInt returns the order of the elliptic curve as a big.Int.
@@ -50,27 +151,73 @@ func OrderAsBigInt() *big.Int {
     return new(big.Int).SetBytes(e.Order())
 }
 
+// HashOption configures the hash function used to derive challenges.
+type HashOption func(*HashConfig)
+
+// HashConfig holds the hash function used to derive challenges.
+// Signer and verifier must use the same configuration, otherwise verification fails.
+type HashConfig struct {
+    New func() hash.Hash
+}
+
+// WithHash selects the hash function used to derive challenges, e.g. sha512.New.
+func WithHash(newHash func() hash.Hash) HashOption {
+    return func(c *HashConfig) {
+        c.New = newHash
+    }
+}
+
+// NewHashConfig applies the options to the default configuration, which uses SHA-256.
+func NewHashConfig(opts ...HashOption) HashConfig {
+    config := HashConfig{New: sha256.New}
+    for _, opt := range opts {
+        opt(&config)
+    }
+    return config
+}
+
 // HashToScalar hashes a series of byte slices into a scalar in Zp*.
 func HashToScalar(inputs ...[]byte) (e.Scalar, error) {
-    hash := sha256.New()
+    return HashToScalarWith(NewHashConfig(), inputs...)
+}
+
+// HashToScalarWith hashes a series of byte slices into a scalar in Zp* using the configured hash function.
+func HashToScalarWith(config HashConfig, inputs ...[]byte) (e.Scalar, error) {
+    hash := config.New()
 
     // Write each input to the hash
     for _, input := range inputs {
         _, err := hash.Write(input)
         if err != nil {
-            return e.Scalar{}, errors.New("failed to hash input")
+            return e.Scalar{}, fmt.Errorf("%w: failed to hash input", ErrHashFailure)
         }
     }
     digest := hash.Sum(nil)
 
     // Convert hash output into a scalar
+    return reduceToScalar(digest), nil
+}
+
+// reduceToScalar interprets data as a big-endian integer and reduces it modulo the curve order.
+// The reduced value is left-padded to the fixed scalar width before conversion, so the result
+// never depends on how circl's SetBytes treats short or over-length inputs. Every conversion of
+// unbounded bytes into a scalar goes through it.
+func reduceToScalar(data []byte) e.Scalar {
+    reduced := new(big.Int).SetBytes(data)
+    reduced.Mod(reduced, OrderAsBigInt())
+
     var scalar e.Scalar
-    order := new(big.Int).SetBytes(e.Order())
-    bigIntScalar := new(big.Int).SetBytes(digest)
-    bigIntScalar.Mod(bigIntScalar, order) // Ensure it is in Zp
-    scalar.SetBytes(bigIntScalar.Bytes())
+    scalar.SetBytes(reduced.FillBytes(make([]byte, e.ScalarSize)))
+    return scalar
+}
 
-    return scalar, nil
+// ScalarFromBigInt converts a non-negative integer into a scalar in Zp,
+// reducing it modulo the curve order with reduceToScalar.
+func ScalarFromBigInt(x *big.Int) (e.Scalar, error) {
+    if x.Sign() < 0 {
+        return e.Scalar{}, ErrNegativeScalar
+    }
+    return reduceToScalar(x.Bytes()), nil
 }
 
 // SerializeG1 serializes a G1 element to bytes.
@@ -78,6 +225,39 @@ func SerializeG1(g *e.G1) []byte {
     return g.Bytes()
 }
 
+// SerializeScalar serializes a scalar to exactly 32 big-endian bytes, keeping leading zero bytes.
+func SerializeScalar(s *e.Scalar) [e.ScalarSize]byte {
+    var out [e.ScalarSize]byte
+    data, _ := s.MarshalBinary()
+    copy(out[e.ScalarSize-len(data):], data)
+    return out
+}
+
+// DeserializeScalar parses a scalar from exactly 32 big-endian bytes.
+// Values that are not below the group order are rejected with ErrNonCanonicalScalar, so every scalar has a single encoding.
+func DeserializeScalar(data []byte) (e.Scalar, error) {
+    var s e.Scalar
+    if len(data) != e.ScalarSize {
+        return e.Scalar{}, fmt.Errorf("%w: got %d bytes, want %d", ErrInvalidScalarEncoding, len(data), e.ScalarSize)
+    }
+    // Check the range explicitly rather than relying on circl, so that a value that could be reduced never decodes
+    if new(big.Int).SetBytes(data).Cmp(OrderAsBigInt()) >= 0 {
+        return e.Scalar{}, ErrNonCanonicalScalar
+    }
+    if err := s.UnmarshalBinary(data); err != nil {
+        return e.Scalar{}, fmt.Errorf("%w: %v", ErrInvalidScalarEncoding, err)
+    }
+    return s, nil
+}
+
+// ScalarsEqual reports whether a and b are equal in constant time.
+// It is the single comparison path for challenges, in verify and open alike: circl's IsEqual compares
+// the field elements without branching, whereas comparing serialized bytes with == or bytes.Equal
+// would stop at the first differing byte.
+func ScalarsEqual(a, b *e.Scalar) bool {
+    return a.IsEqual(b) == 1
+}
+
 // SerializeGt serializes a Gt element to bytes.
 func SerializeGt(g *e.Gt) []byte {
     data, _ := g.MarshalBinary()
@@ -87,4 +267,88 @@ func SerializeGt(g *e.Gt) []byte {
 // SerializeString serializes a string to bytes.
 func SerializeString(s string) []byte {
     return []byte(s)
-}
\ No newline at end of file
+}
+
+// DigestMessages hashes a vector of messages into the 32-byte digest that is signed.
+// Sign(m) signs DigestMessages([]string{m}), so a caller hashing messages the same way
+// can sign and verify the digest directly with SignPrehashed and VerifyPrehashed.
+func DigestMessages(msgs []string) [32]byte {
+    return sha256.Sum256(SerializeMessages(msgs))
+}
+
+// SerializeMessages serializes a vector of messages to bytes.
+// Each message is prefixed with its length as an 8-byte big-endian integer,
+// so that the encoding of the vector is unambiguous.
+func SerializeMessages(msgs []string) []byte {
+    var data []byte
+    for _, m := range msgs {
+        var length [8]byte
+        binary.BigEndian.PutUint64(length[:], uint64(len(m)))
+        data = append(data, length[:]...)
+        data = append(data, m...)
+    }
+    return data
+}
+
+// recordDomain separates record digests from the digests of message vectors with the same encoding.
+const recordDomain = "BBS-RECORD"
+
+// CanonicalizeRecord serializes a record of named fields to bytes.
+// The fields are sorted by key and encoded as key, value, key, value, ... with SerializeMessages,
+// so two maps with the same entries always produce the same bytes, whatever their iteration order.
+func CanonicalizeRecord(m map[string]string) []byte {
+    keys := make([]string, 0, len(m))
+    for k := range m {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+
+    fields := make([]string, 0, 2*len(keys))
+    for _, k := range keys {
+        fields = append(fields, k, m[k])
+    }
+    return SerializeMessages(fields)
+}
+
+// DigestRecord hashes a record into the 32-byte digest that is signed by SignRecord.
+// The digest is domain separated, so a record never signs the same digest as a message vector.
+func DigestRecord(m map[string]string) [32]byte {
+    return sha256.Sum256(append([]byte(recordDomain), CanonicalizeRecord(m)...))
+}
+
+// attributeGeneratorDomain is the domain separation tag for deriving the per-attribute generators.
+const attributeGeneratorDomain = "BBS-ATTRIBUTE-GENERATOR"
+
+// AttributeGenerator derives the G1 generator G_i used to commit to the attribute at the given index.
+// Hashing to the curve makes the discrete logarithms between the generators and h unknown.
+func AttributeGenerator(index int) *e.G1 {
+    var input [8]byte
+    binary.BigEndian.PutUint64(input[:], uint64(index))
+    generator := new(e.G1)
+    generator.Hash(input[:], []byte(attributeGeneratorDomain))
+    return generator
+}
+
+// CommitAttribute computes the commitment G_index^{H(attr)} * h^{blinding} to an attribute.
+func CommitAttribute(index int, attr string, h *e.G1, blinding *e.Scalar) (*e.G1, error) {
+    value, err := HashToScalar(SerializeString(attr))
+    if err != nil {
+        return nil, err
+    }
+    commitment := new(e.G1)
+    commitment.ScalarMult(&value, AttributeGenerator(index))
+    hBlinding := new(e.G1)
+    hBlinding.ScalarMult(blinding, h)
+    commitment.Add(commitment, hBlinding)
+    return commitment, nil
+}
+
+// linkTagDomain is the domain separation tag for hashing epochs to G1.
+const linkTagDomain = "BBS-LINK-TAG"
+
+// LinkBase hashes an epoch to the G1 element H(epoch) used as the base of linkability tags.
+func LinkBase(epoch []byte) *e.G1 {
+    base := new(e.G1)
+    base.Hash(epoch, []byte(linkTagDomain))
+    return base
+}
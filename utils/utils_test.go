@@ -1,9 +1,16 @@
 package utils
 
 import (
+    "bytes"
+    "crypto/rand"
+    "crypto/sha256"
+    "errors"
+    "fmt"
+    "hash"
     "testing"
     "math/big"
 
+    e "github.com/cloudflare/circl/ecc/bls12381"
     "github.com/stretchr/testify/assert"
 )
 
@@ -38,6 +45,18 @@ func TestRandomG1Element(t *testing.T) {
 
     // Assert the element is not the identity element
     assert.False(t, element.IsIdentity(), "RandomG1Element should not generate the identity element")
+
+    // Every draw is a non-identity element of the prime-order subgroup
+    for i := 0; i < 100; i++ {
+        element, err := RandomG1Element()
+        assert.NoError(t, err, "RandomG1Element should not return an error")
+        assert.False(t, element.IsIdentity(), "RandomG1Element should not generate the identity element")
+        assert.True(t, element.IsOnG1(), "RandomG1Element should generate an element of G1")
+    }
+
+    // A failing reader is reported
+    _, err = RandomG1ElementFromReader(failingReader{})
+    assert.ErrorIs(t, err, ErrRandomnessFailure, "RandomG1ElementFromReader should wrap ErrRandomnessFailure")
 }
 
 // TestHashToScalar tests the HashToScalar function.
@@ -52,4 +71,271 @@ func TestHashToScalar(t *testing.T) {
 
     // Assert the scalar is not zero
     assert.False(t, scalar.IsZero() == 1, "HashToScalar should not generate a zero scalar")
-}
\ No newline at end of file
+}
+
+// TestSerializeMessages tests the SerializeMessages function.
+func TestSerializeMessages(t *testing.T) {
+    // Moving bytes between messages must change the encoding
+    a := SerializeMessages([]string{"ab", "c"})
+    b := SerializeMessages([]string{"a", "bc"})
+    assert.NotEqual(t, a, b, "SerializeMessages should be unambiguous")
+
+    // Each message is prefixed with its 8-byte length
+    assert.Equal(t, 8+2+8+1, len(a), "SerializeMessages should length-prefix each message")
+}
+
+// TestCanonicalizeRecord tests that CanonicalizeRecord is independent of insertion order and unambiguous.
+func TestCanonicalizeRecord(t *testing.T) {
+    a := map[string]string{}
+    a["name"] = "Anna"
+    a["surname"] = "Gut"
+    b := map[string]string{}
+    b["surname"] = "Gut"
+    b["name"] = "Anna"
+    assert.Equal(t, CanonicalizeRecord(a), CanonicalizeRecord(b), "CanonicalizeRecord should not depend on insertion order")
+    assert.Equal(t, SerializeMessages([]string{"name", "Anna", "surname", "Gut"}), CanonicalizeRecord(a), "CanonicalizeRecord should encode the sorted fields")
+
+    // Moving bytes between a key and its value must change the encoding
+    assert.NotEqual(t, CanonicalizeRecord(map[string]string{"ab": "c"}), CanonicalizeRecord(map[string]string{"a": "bc"}), "CanonicalizeRecord should be unambiguous")
+
+    // A record and the message vector with the same encoding must not share a digest
+    assert.NotEqual(t, DigestMessages([]string{"ab", "c"}), DigestRecord(map[string]string{"ab": "c"}), "DigestRecord should be domain separated")
+}
+
+// failingReader is a source of randomness that always fails.
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+    return 0, errors.New("entropy source unavailable")
+}
+
+// TestRandomnessFailure tests that random generation failures wrap ErrRandomnessFailure.
+func TestRandomnessFailure(t *testing.T) {
+    _, err := RandomScalarFromReader(failingReader{})
+    assert.ErrorIs(t, err, ErrRandomnessFailure, "RandomScalarFromReader should wrap ErrRandomnessFailure")
+}
+
+// failingHash is a hash function whose Write always fails.
+type failingHash struct {
+    hash.Hash
+}
+
+func newFailingHash() hash.Hash {
+    return failingHash{sha256.New()}
+}
+
+func (failingHash) Write(p []byte) (int, error) {
+    return 0, errors.New("hash unavailable")
+}
+
+// TestHashFailure tests that hashing failures wrap ErrHashFailure and survive further wrapping.
+func TestHashFailure(t *testing.T) {
+    _, err := HashToScalarWith(NewHashConfig(WithHash(newFailingHash)), []byte("input"))
+    assert.ErrorIs(t, err, ErrHashFailure, "HashToScalarWith should wrap ErrHashFailure")
+
+    _, err = NewTranscript("test", WithHash(newFailingHash)).Challenge()
+    assert.ErrorIs(t, err, ErrHashFailure, "Challenge should wrap ErrHashFailure")
+
+    wrapped := fmt.Errorf("outer: %w", fmt.Errorf("inner: %w", err))
+    assert.True(t, errors.Is(wrapped, ErrHashFailure), "ErrHashFailure should survive wrapping")
+    assert.False(t, errors.Is(wrapped, ErrRandomnessFailure), "A hash failure should not look like a randomness failure")
+}
+
+// onesReader is a broken source of randomness that returns only 0xFF bytes.
+type onesReader struct{}
+
+func (onesReader) Read(p []byte) (int, error) {
+    for i := range p {
+        p[i] = 0xFF
+    }
+    return len(p), nil
+}
+
+// TestRandomBelow tests that randomBelow draws like crypto/rand.Int but gives up on a reader it cannot use.
+func TestRandomBelow(t *testing.T) {
+    // The same stream yields the same values as crypto/rand.Int
+    seed := make([]byte, 64*e.ScalarSize)
+    _, err := rand.Read(seed)
+    assert.NoError(t, err, "Read should not return an error")
+    ours, theirs := bytes.NewReader(seed), bytes.NewReader(seed)
+    for i := 0; i < 16; i++ {
+        got, err := randomBelow(ours, OrderAsBigInt())
+        assert.NoError(t, err, "randomBelow should not return an error")
+        want, err := rand.Int(theirs, OrderAsBigInt())
+        assert.NoError(t, err, "Int should not return an error")
+        assert.Equal(t, 0, got.Cmp(want), "randomBelow should match crypto/rand.Int")
+    }
+
+    // Every candidate from a 0xFF reader is at least the order, so the draw fails instead of hanging
+    _, err = RandomScalarFromReader(onesReader{})
+    assert.ErrorIs(t, err, ErrRandomnessFailure, "RandomScalarFromReader should reject a 0xFF reader")
+}
+
+// TestScalarFromBigInt tests the ScalarFromBigInt function.
+func TestScalarFromBigInt(t *testing.T) {
+    order := OrderAsBigInt()
+
+    // x = 0
+    scalar, err := ScalarFromBigInt(big.NewInt(0))
+    assert.NoError(t, err, "ScalarFromBigInt should not return an error for 0")
+    assert.True(t, scalar.IsZero() == 1, "0 should convert to the zero scalar")
+
+    // x = order reduces to zero
+    scalar, err = ScalarFromBigInt(order)
+    assert.NoError(t, err, "ScalarFromBigInt should not return an error for the order")
+    assert.True(t, scalar.IsZero() == 1, "The order should reduce to the zero scalar")
+
+    // x = order + 1 reduces to one
+    scalar, err = ScalarFromBigInt(new(big.Int).Add(order, big.NewInt(1)))
+    assert.NoError(t, err, "ScalarFromBigInt should not return an error for order + 1")
+    var one e.Scalar
+    one.SetOne()
+    assert.True(t, scalar.IsEqual(&one) == 1, "order + 1 should reduce to one")
+
+    // Negative values are rejected
+    _, err = ScalarFromBigInt(big.NewInt(-1))
+    assert.ErrorIs(t, err, ErrNegativeScalar, "ScalarFromBigInt should reject negative values")
+}
+
+// TestReduceToScalar tests that reduceToScalar yields value mod order for values around multiples of the order.
+func TestReduceToScalar(t *testing.T) {
+    order := OrderAsBigInt()
+    max := new(big.Int).Lsh(big.NewInt(1), 8*64)
+
+    var values []*big.Int
+    for _, multiple := range []int64{0, 1, 2, 3} {
+        base := new(big.Int).Mul(order, big.NewInt(multiple))
+        for delta := int64(-2); delta <= 2; delta++ {
+            value := new(big.Int).Add(base, big.NewInt(delta))
+            if value.Sign() >= 0 {
+                values = append(values, value)
+            }
+        }
+    }
+    // The largest 32- and 64-byte inputs, e.g. SHA-256 and SHA-512 digests of all ones
+    values = append(values, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)), new(big.Int).Sub(max, big.NewInt(1)))
+
+    for _, value := range values {
+        for _, data := range [][]byte{value.Bytes(), value.FillBytes(make([]byte, 64))} {
+            scalar := reduceToScalar(data)
+            encoded := SerializeScalar(&scalar)
+            expected := new(big.Int).Mod(value, order)
+            assert.Zero(t, expected.Cmp(new(big.Int).SetBytes(encoded[:])), "The scalar should equal the value mod order for %s", value)
+        }
+    }
+}
+
+// TestScalarByteOrder tests that SerializeScalar, MarshalBinary and big.Int agree on big-endian byte order.
+func TestScalarByteOrder(t *testing.T) {
+    for i := 0; i < 16; i++ {
+        s, err := RandomScalar()
+        assert.NoError(t, err, "RandomScalar should not return an error")
+
+        // SerializeScalar has MarshalBinary semantics
+        data := SerializeScalar(&s)
+        marshaled, err := s.MarshalBinary()
+        assert.NoError(t, err, "MarshalBinary should not return an error")
+        assert.Equal(t, marshaled, data[:], "SerializeScalar should match MarshalBinary")
+
+        // The bytes read as a big-endian integer give back the same scalar through ScalarFromBigInt
+        value := new(big.Int).SetBytes(data[:])
+        fromBigInt, err := ScalarFromBigInt(value)
+        assert.NoError(t, err, "ScalarFromBigInt should not return an error")
+        assert.Equal(t, 1, fromBigInt.IsEqual(&s), "ScalarFromBigInt should read the bytes as big-endian")
+        assert.Equal(t, value.FillBytes(make([]byte, e.ScalarSize)), data[:], "SerializeScalar should match big.Int.FillBytes")
+
+        // Reversing the bytes does not decode to the same scalar
+        reversed := make([]byte, e.ScalarSize)
+        for j := range data {
+            reversed[j] = data[e.ScalarSize-1-j]
+        }
+        decoded, err := DeserializeScalar(reversed)
+        assert.True(t, err != nil || decoded.IsEqual(&s) == 0, "A little-endian encoding should not decode to the same scalar")
+    }
+}
+
+// TestSerializeScalar tests that scalars serialize to 32 bytes and deserialize back.
+func TestSerializeScalar(t *testing.T) {
+    var zero, one, max e.Scalar
+    one.SetOne()
+    max.SetOne()
+    max.Neg() // order - 1
+
+    // Leading zeros of small values are kept
+    data := SerializeScalar(&one)
+    expected := make([]byte, e.ScalarSize)
+    expected[e.ScalarSize-1] = 1
+    assert.Equal(t, expected, data[:], "SerializeScalar(1) should be 31 zero bytes followed by 1")
+
+    // Zero is 32 zero bytes
+    data = SerializeScalar(&zero)
+    assert.Equal(t, make([]byte, e.ScalarSize), data[:], "SerializeScalar(0) should be 32 zero bytes")
+
+    // The largest scalar is order - 1
+    data = SerializeScalar(&max)
+    orderMinusOne := new(big.Int).Sub(OrderAsBigInt(), big.NewInt(1))
+    assert.Equal(t, orderMinusOne.FillBytes(make([]byte, e.ScalarSize)), data[:], "SerializeScalar(order - 1) should match the order")
+
+    // Every value round-trips
+    for _, s := range []e.Scalar{zero, one, max} {
+        data := SerializeScalar(&s)
+        decoded, err := DeserializeScalar(data[:])
+        assert.NoError(t, err, "DeserializeScalar should not return an error")
+        assert.Equal(t, 1, decoded.IsEqual(&s), "DeserializeScalar should invert SerializeScalar")
+    }
+
+    // Wrong lengths and unreduced values are rejected
+    _, err := DeserializeScalar(data[1:])
+    assert.ErrorIs(t, err, ErrInvalidScalarEncoding, "DeserializeScalar should reject short input")
+    _, err = DeserializeScalar(OrderAsBigInt().FillBytes(make([]byte, e.ScalarSize)))
+    assert.ErrorIs(t, err, ErrInvalidScalarEncoding, "DeserializeScalar should reject the order itself")
+}
+
+// TestDeserializeScalarNonCanonical tests that DeserializeScalar rejects the order and larger values.
+func TestDeserializeScalarNonCanonical(t *testing.T) {
+    order := OrderAsBigInt()
+    for _, value := range []*big.Int{order, new(big.Int).Add(order, big.NewInt(1)), new(big.Int).Lsh(big.NewInt(1), 8*e.ScalarSize-1)} {
+        _, err := DeserializeScalar(value.FillBytes(make([]byte, e.ScalarSize)))
+        assert.ErrorIs(t, err, ErrNonCanonicalScalar, "DeserializeScalar should reject %v", value)
+        assert.ErrorIs(t, err, ErrInvalidScalarEncoding, "ErrNonCanonicalScalar should wrap ErrInvalidScalarEncoding")
+    }
+
+    // The largest scalar still decodes
+    orderMinusOne := new(big.Int).Sub(order, big.NewInt(1))
+    decoded, err := DeserializeScalar(orderMinusOne.FillBytes(make([]byte, e.ScalarSize)))
+    assert.NoError(t, err, "DeserializeScalar should accept order - 1")
+    expected, err := ScalarFromBigInt(orderMinusOne)
+    assert.NoError(t, err, "ScalarFromBigInt should not return an error")
+    assert.Equal(t, 1, decoded.IsEqual(&expected), "DeserializeScalar should decode order - 1")
+}
+
+// TestScalarsEqual tests the ScalarsEqual function.
+func TestScalarsEqual(t *testing.T) {
+    var a, b, c e.Scalar
+    a.SetUint64(42)
+    b.SetUint64(42)
+    c.SetUint64(43)
+
+    assert.True(t, ScalarsEqual(&a, &b), "ScalarsEqual should return true for equal scalars")
+    assert.False(t, ScalarsEqual(&a, &c), "ScalarsEqual should return false for different scalars")
+}
+
+// TestLogDigest tests that the running log hash depends on the entries, their boundaries and their order.
+func TestLogDigest(t *testing.T) {
+    digest := func(entries ...string) [32]byte {
+        d := NewLogDigest()
+        for _, entry := range entries {
+            d.Append([]byte(entry))
+        }
+        return d.Sum()
+    }
+
+    assert.Equal(t, digest("a", "b"), digest("a", "b"), "LogDigest should be deterministic")
+    assert.NotEqual(t, digest("a", "b"), digest("b", "a"), "LogDigest should depend on the order of the entries")
+    assert.NotEqual(t, digest("ab", "c"), digest("a", "bc"), "LogDigest should be unambiguous")
+    assert.NotEqual(t, digest(), digest(""), "LogDigest should count empty entries")
+
+    d := NewLogDigest()
+    d.Append([]byte("a"))
+    assert.Equal(t, uint64(1), d.Len(), "Len should count the appended entries")
+}
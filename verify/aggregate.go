@@ -0,0 +1,48 @@
+package verify
+
+import (
+    "fmt"
+
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// VerifyAggregate checks a batch of BBS signatures, e.g. many messages signed by one user,
+// and reports whether all of them are valid.
+//
+// The signatures cannot be combined with a random linear combination: the challenge c of each
+// signature is a hash over its own R3, so every R3 has to be recomputed on its own and no shared
+// group equation remains to batch. Knowing that the signatures come from the same A does not help
+// either, because T3 = A * h^(alpha + beta) is re-randomized in every signature. Instead, each R3 is
+// computed as in Verify, with the pairings grouped by their G2 argument (see proof.R3Grouped).
+// Caching e(h, w), e(h, g2) and e(g1, g2) and applying the exponents with Gt.Exp is slower still,
+// since each Gt exponentiation costs more than the G1 multiplications the grouping needs (see BenchmarkComputeR3).
+//
+// Parameters:
+//   - publicKey: The public key of the system (gpk = (g1, g2, h, u, v, w)).
+//   - msgs: The messages being verified.
+//   - signatures: The signatures to verify, where signatures[i] is over msgs[i].
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - bool: True if every signature is valid, false otherwise.
+//   - error: An error if the batch is empty, the lengths differ, or a signature is malformed.
+func VerifyAggregate(publicKey models.PublicKey, msgs []string, signatures []models.Signature, opts ...utils.HashOption) (bool, error) {
+    if len(msgs) == 0 {
+        return false, utils.ErrEmptyMessageVector
+    }
+    if len(msgs) != len(signatures) {
+        return false, fmt.Errorf("%w: %d messages and %d signatures", utils.ErrLengthMismatch, len(msgs), len(signatures))
+    }
+
+    for i := range msgs {
+        valid, err := verifyDigest(publicKey, utils.DigestMessages([]string{msgs[i]}), signatures[i], opts)
+        if err != nil {
+            return false, fmt.Errorf("signature %d: %w", i, err)
+        }
+        if !valid {
+            return false, nil
+        }
+    }
+    return true, nil
+}
@@ -0,0 +1,44 @@
+package verify_test
+
+import (
+    "fmt"
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/utils"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestVerifyAggregate tests that a batch from one user verifies and a single tampered signature fails it.
+func TestVerifyAggregate(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    msgs := make([]string, 50)
+    signatures := make([]models.Signature, 50)
+    for i := range msgs {
+        msgs[i] = fmt.Sprintf("message %d", i)
+        signatures[i], err = sign.Sign(result.PublicKey, result.Users[0], msgs[i])
+        assert.NoError(t, err, "Sign should not return an error")
+    }
+
+    valid, err := verify.VerifyAggregate(result.PublicKey, msgs, signatures)
+    assert.NoError(t, err, "VerifyAggregate should not return an error")
+    assert.True(t, valid, "VerifyAggregate should accept a batch of valid signatures")
+
+    // Swapping the message of one signature fails the whole batch
+    tampered := append([]string(nil), msgs...)
+    tampered[17] = "tampered"
+    valid, err = verify.VerifyAggregate(result.PublicKey, tampered, signatures)
+    assert.NoError(t, err, "VerifyAggregate should not return an error")
+    assert.False(t, valid, "VerifyAggregate should reject a batch with a tampered signature")
+
+    // Mismatched and empty batches are rejected
+    _, err = verify.VerifyAggregate(result.PublicKey, msgs[:3], signatures[:2])
+    assert.ErrorIs(t, err, utils.ErrLengthMismatch, "VerifyAggregate should reject mismatched lengths")
+    _, err = verify.VerifyAggregate(result.PublicKey, nil, nil)
+    assert.ErrorIs(t, err, utils.ErrEmptyMessageVector, "VerifyAggregate should reject an empty batch")
+}
@@ -0,0 +1,63 @@
+package verify
+
+import (
+    "errors"
+    "fmt"
+
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// ErrAttributeNotDisclosed is returned when a revealed attribute has no opening in the signature.
+var ErrAttributeNotDisclosed = errors.New("attribute not disclosed")
+
+// VerifyAttributes checks the validity of an attribute signature and of the revealed attributes.
+// The signature proves that a group member signed the commitments; each revealed attribute
+// is checked against its commitment with the opening carried in the signature.
+// Attributes that are not revealed stay hidden.
+//
+// Parameters:
+//   - publicKey: The public key of the system (gpk = (g1, g2, h, u, v, w)).
+//   - revealed: The disclosed attributes, by attribute index.
+//   - signature: The attribute signature to verify.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - bool: True if the signature is valid and every revealed attribute matches its commitment, false otherwise.
+//   - error: ErrAttributeNotDisclosed if a revealed attribute cannot be checked, or an error if the verification process fails.
+func VerifyAttributes(publicKey models.PublicKey, revealed map[int]string, signature models.AttributeSignature, opts ...utils.HashOption) (bool, error) {
+    if len(signature.Commitments) == 0 {
+        return false, utils.ErrEmptyMessageVector
+    }
+    for _, commitment := range signature.Commitments {
+        if commitment == nil {
+            return false, fmt.Errorf("%w: attribute commitment is missing", ErrMalformedSignature)
+        }
+    }
+
+    // Check the revealed attributes against their commitments
+    for i, attr := range revealed {
+        if i < 0 || i >= len(signature.Commitments) {
+            return false, fmt.Errorf("%w: index %d is out of range", ErrAttributeNotDisclosed, i)
+        }
+        opening, ok := signature.Openings[i]
+        if !ok || opening == nil {
+            return false, fmt.Errorf("%w: no opening for index %d", ErrAttributeNotDisclosed, i)
+        }
+        commitment, err := utils.CommitAttribute(i, attr, publicKey.H, opening)
+        if err != nil {
+            return false, err
+        }
+        if !commitment.IsEqual(signature.Commitments[i]) {
+            return false, nil
+        }
+    }
+
+    // Check that the commitments were signed by a group member
+    extend := func(t *utils.Transcript, s models.Signature) {
+        for _, commitment := range signature.Commitments {
+            t.AppendG1("attribute", commitment)
+        }
+    }
+    return verifyWithExtension(publicKey, utils.DigestMessages(nil), signature.Signature, extend, opts, nil)
+}
@@ -0,0 +1,44 @@
+package verify_test
+
+import (
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestVerifyAttributes tests that one of three signed attributes can be revealed and checked.
+func TestVerifyAttributes(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    attrs := []string{"name=Alice", "age=30", "country=PL"}
+    signature, err := sign.SignAttributes(result.PublicKey, result.Users[0], attrs)
+    assert.NoError(t, err, "SignAttributes should not return an error")
+
+    // Reveal the second attribute only
+    disclosed := signature.Disclose(1)
+    assert.Len(t, disclosed.Openings, 1, "Disclose should keep a single opening")
+    valid, err := verify.VerifyAttributes(result.PublicKey, map[int]string{1: "age=30"}, disclosed)
+    assert.NoError(t, err, "VerifyAttributes should not return an error")
+    assert.True(t, valid, "VerifyAttributes should accept the revealed attribute")
+
+    // A different value for the revealed attribute is rejected
+    valid, err = verify.VerifyAttributes(result.PublicKey, map[int]string{1: "age=18"}, disclosed)
+    assert.NoError(t, err, "VerifyAttributes should not return an error")
+    assert.False(t, valid, "VerifyAttributes should reject a wrong attribute value")
+
+    // A hidden attribute cannot be revealed
+    _, err = verify.VerifyAttributes(result.PublicKey, map[int]string{0: "name=Alice"}, disclosed)
+    assert.ErrorIs(t, err, verify.ErrAttributeNotDisclosed, "VerifyAttributes should reject a hidden attribute")
+
+    // Replacing a commitment invalidates the signature
+    tampered := disclosed
+    tampered.Commitments = append(tampered.Commitments[:0:0], disclosed.Commitments...)
+    tampered.Commitments[2] = disclosed.Commitments[0]
+    valid, err = verify.VerifyAttributes(result.PublicKey, map[int]string{1: "age=30"}, tampered)
+    assert.NoError(t, err, "VerifyAttributes should not return an error")
+    assert.False(t, valid, "VerifyAttributes should reject replaced commitments")
+}
@@ -0,0 +1,87 @@
+package verify
+
+import (
+    "errors"
+    "fmt"
+
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// ErrUnknownBatchMode is returned when VerifyBatch is called with a mode it does not define.
+var ErrUnknownBatchMode = errors.New("unknown batch mode")
+
+// BatchMode selects how VerifyBatch treats a batch of signatures.
+type BatchMode int
+
+const (
+    // ModeAllOrNothing reports only whether the whole batch is valid and stops at the first
+    // invalid signature. It is the default, since it does the least work on a bad batch.
+    ModeAllOrNothing BatchMode = iota
+    // ModePerSignature checks every signature and reports each result, for diagnostics.
+    ModePerSignature
+)
+
+// BatchResult is the outcome of VerifyBatch.
+type BatchResult struct {
+    // Valid is true if every signature in the batch is valid.
+    Valid bool
+    // Results holds the validity of each signature in ModePerSignature and is nil in ModeAllOrNothing.
+    Results []bool
+}
+
+// VerifyBatch checks a batch of BBS signatures in the given mode.
+//
+// ModeAllOrNothing cannot use a single random-combination check: as explained at VerifyAggregate,
+// every challenge hashes its own R3, so each signature is recomputed on its own. What it saves over
+// ModePerSignature is the work after the first invalid signature; on a clean batch both modes cost
+// the same. Both modes compute R3 with two Miller loops per signature.
+//
+// In ModePerSignature a malformed signature is reported as invalid and the batch is still checked
+// to the end; in ModeAllOrNothing it is returned as ErrMalformedSignature.
+//
+// Parameters:
+//   - publicKey: The public key of the system (gpk = (g1, g2, h, u, v, w)).
+//   - msgs: The messages being verified.
+//   - signatures: The signatures to verify, where signatures[i] is over msgs[i].
+//   - mode: Whether to stop at the first invalid signature or report every result.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - BatchResult: Whether the batch is valid and, in ModePerSignature, the result of each signature.
+//   - error: ErrUnknownBatchMode for an undefined mode, or an error if the batch is empty, the lengths differ, or the verification process fails.
+func VerifyBatch(publicKey models.PublicKey, msgs []string, signatures []models.Signature, mode BatchMode, opts ...utils.HashOption) (BatchResult, error) {
+    switch mode {
+    case ModeAllOrNothing:
+        valid, err := VerifyAggregate(publicKey, msgs, signatures, opts...)
+        if err != nil {
+            return BatchResult{}, err
+        }
+        return BatchResult{Valid: valid}, nil
+    case ModePerSignature:
+        return verifyEach(publicKey, msgs, signatures, opts)
+    default:
+        return BatchResult{}, fmt.Errorf("%w: %d", ErrUnknownBatchMode, mode)
+    }
+}
+
+// verifyEach checks every signature of the batch and records the result of each.
+func verifyEach(publicKey models.PublicKey, msgs []string, signatures []models.Signature, opts []utils.HashOption) (BatchResult, error) {
+    if len(msgs) == 0 {
+        return BatchResult{}, utils.ErrEmptyMessageVector
+    }
+    if len(msgs) != len(signatures) {
+        return BatchResult{}, fmt.Errorf("%w: %d messages and %d signatures", utils.ErrLengthMismatch, len(msgs), len(signatures))
+    }
+
+    result := BatchResult{Valid: true, Results: make([]bool, len(msgs))}
+    for i := range msgs {
+        valid, err := verifyDigest(publicKey, utils.DigestMessages([]string{msgs[i]}), signatures[i], opts)
+        if err != nil && !errors.Is(err, ErrMalformedSignature) {
+            return BatchResult{}, fmt.Errorf("signature %d: %w", i, err)
+        }
+        result.Results[i] = valid
+        result.Valid = result.Valid && valid
+    }
+    return result, nil
+}
@@ -0,0 +1,109 @@
+package verify_test
+
+import (
+    "fmt"
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/utils"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// signBatch signs n distinct messages as the first user of the group.
+func signBatch(tb testing.TB, result models.KeyGenResult, n int) ([]string, []models.Signature) {
+    msgs := make([]string, n)
+    signatures := make([]models.Signature, n)
+    for i := range msgs {
+        msgs[i] = fmt.Sprintf("message %d", i)
+        signature, err := sign.Sign(result.PublicKey, result.Users[0], msgs[i])
+        if err != nil {
+            tb.Fatal(err)
+        }
+        signatures[i] = signature
+    }
+    return msgs, signatures
+}
+
+// TestVerifyBatch tests that both batch modes agree on a clean batch and on a batch with a bad signature.
+func TestVerifyBatch(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    msgs, signatures := signBatch(t, result, 8)
+
+    // Both modes accept a clean batch
+    allOrNothing, err := verify.VerifyBatch(result.PublicKey, msgs, signatures, verify.ModeAllOrNothing)
+    assert.NoError(t, err, "VerifyBatch should not return an error")
+    assert.True(t, allOrNothing.Valid, "ModeAllOrNothing should accept a clean batch")
+    assert.Nil(t, allOrNothing.Results, "ModeAllOrNothing should not report individual results")
+
+    perSignature, err := verify.VerifyBatch(result.PublicKey, msgs, signatures, verify.ModePerSignature)
+    assert.NoError(t, err, "VerifyBatch should not return an error")
+    assert.True(t, perSignature.Valid, "ModePerSignature should accept a clean batch")
+    assert.Equal(t, []bool{true, true, true, true, true, true, true, true}, perSignature.Results, "Every signature should be valid")
+
+    // Both modes reject a batch with one tampered message, and ModePerSignature pinpoints it
+    tampered := append([]string(nil), msgs...)
+    tampered[3] = "tampered"
+    allOrNothing, err = verify.VerifyBatch(result.PublicKey, tampered, signatures, verify.ModeAllOrNothing)
+    assert.NoError(t, err, "VerifyBatch should not return an error")
+    assert.False(t, allOrNothing.Valid, "ModeAllOrNothing should reject the batch")
+
+    perSignature, err = verify.VerifyBatch(result.PublicKey, tampered, signatures, verify.ModePerSignature)
+    assert.NoError(t, err, "VerifyBatch should not return an error")
+    assert.False(t, perSignature.Valid, "ModePerSignature should reject the batch")
+    assert.Equal(t, []bool{true, true, true, false, true, true, true, true}, perSignature.Results, "Only the tampered signature should be invalid")
+
+    // A malformed signature is an error in ModeAllOrNothing and an invalid result in ModePerSignature
+    malformed := append([]models.Signature(nil), signatures...)
+    malformed[5].T3 = nil
+    _, err = verify.VerifyBatch(result.PublicKey, msgs, malformed, verify.ModeAllOrNothing)
+    assert.ErrorIs(t, err, verify.ErrMalformedSignature, "ModeAllOrNothing should report the malformed signature")
+    perSignature, err = verify.VerifyBatch(result.PublicKey, msgs, malformed, verify.ModePerSignature)
+    assert.NoError(t, err, "ModePerSignature should not return an error for a malformed signature")
+    assert.False(t, perSignature.Results[5], "The malformed signature should be invalid")
+
+    // Mismatched lengths are reported as utils.ErrLengthMismatch in both modes
+    for _, mode := range []verify.BatchMode{verify.ModeAllOrNothing, verify.ModePerSignature} {
+        _, err = verify.VerifyBatch(result.PublicKey, msgs[:7], signatures, mode)
+        assert.ErrorIs(t, err, utils.ErrLengthMismatch, "VerifyBatch should reject mismatched lengths in mode %d", mode)
+    }
+}
+
+// BenchmarkVerifyBatch contrasts the two batch modes over 1000 signatures, on a clean batch
+// and on a batch whose first signature is invalid.
+func BenchmarkVerifyBatch(b *testing.B) {
+    result, err := keygen.KeyGen(1)
+    if err != nil {
+        b.Fatal(err)
+    }
+    msgs, signatures := signBatch(b, result, 1000)
+    failing := append([]string(nil), msgs...)
+    failing[0] = "tampered"
+
+    for _, batch := range []struct {
+        name string
+        msgs []string
+    }{
+        {"Clean", msgs},
+        {"FirstInvalid", failing},
+    } {
+        for _, mode := range []struct {
+            name string
+            mode verify.BatchMode
+        }{
+            {"AllOrNothing", verify.ModeAllOrNothing},
+            {"PerSignature", verify.ModePerSignature},
+        } {
+            b.Run(batch.name+"/"+mode.name, func(b *testing.B) {
+                for i := 0; i < b.N; i++ {
+                    if _, err := verify.VerifyBatch(result.PublicKey, batch.msgs, signatures, mode.mode); err != nil {
+                        b.Fatal(err)
+                    }
+                }
+            })
+        }
+    }
+}
@@ -0,0 +1,88 @@
+package verify
+
+import (
+    "testing"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/stretchr/testify/assert"
+)
+
+// cachedBases holds the pairings of the fixed bases of R3, which depend only on the public key.
+type cachedBases struct {
+    hw, hg2, g1g2 *e.Gt
+}
+
+// newCachedBases computes e(h, w), e(h, g2) and e(g1, g2) once.
+func newCachedBases(g1, h *e.G1, g2, w *e.G2) cachedBases {
+    return cachedBases{hw: e.Pair(h, w), hg2: e.Pair(h, g2), g1g2: e.Pair(g1, g2)}
+}
+
+// computeR3Cached computes the same R3 as computeR3 from the cached base pairings, applying the
+// exponents with Gt.Exp. Only the pairings involving T3 are computed per signature:
+// R3 = e(T3, g2)^{s_x} * e(T3, w)^{c} * e(h, w)^{-s_alpha - s_beta} * e(h, g2)^{-s_delta1 - s_delta2} * e(g1, g2)^{-c}.
+func computeR3Cached(bases cachedBases, T3 *e.G1, g2 *e.G2, SX *e.Scalar, w *e.G2, SAlpha, SBeta, SDelta1, SDelta2 *e.Scalar, C e.Scalar) *e.Gt {
+    sAlphaBeta := new(e.Scalar)
+    sAlphaBeta.Add(SAlpha, SBeta)
+    sAlphaBeta.Neg()
+    sDelta := new(e.Scalar)
+    sDelta.Add(SDelta1, SDelta2)
+    sDelta.Neg()
+    minusC := new(e.Scalar)
+    minusC.Set(&C)
+    minusC.Neg()
+
+    R3 := e.ProdPair([]*e.G1{T3, T3}, []*e.G2{g2, w}, []*e.Scalar{SX, &C})
+    term := new(e.Gt)
+    term.Exp(bases.hw, sAlphaBeta)
+    R3.Mul(R3, term)
+    term.Exp(bases.hg2, sDelta)
+    R3.Mul(R3, term)
+    term.Exp(bases.g1g2, minusC)
+    R3.Mul(R3, term)
+    return R3
+}
+
+// r3Inputs returns fixed R3 inputs shared by the cached-base test and benchmark.
+func r3Inputs() (g1, T3, h *e.G1, g2, w *e.G2, values []e.Scalar) {
+    g1 = e.G1Generator()
+    g2 = e.G2Generator()
+    values = make([]e.Scalar, 7)
+    for i := range values {
+        values[i].SetUint64(uint64(1000003 * (i + 1)))
+    }
+    T3 = new(e.G1)
+    T3.ScalarMult(&values[0], g1)
+    h = new(e.G1)
+    h.ScalarMult(&values[1], g1)
+    w = new(e.G2)
+    w.ScalarMult(&values[2], g2)
+    return g1, T3, h, g2, w, values
+}
+
+// TestComputeR3Cached tests that caching the base pairings yields the same R3 as computeR3.
+func TestComputeR3Cached(t *testing.T) {
+    g1, T3, h, g2, w, values := r3Inputs()
+    expected, err := computeR3(T3, g1, g2, &values[3], h, w, &values[4], &values[5], &values[6], &values[3], values[4])
+    assert.NoError(t, err, "computeR3 should not return an error")
+
+    cached := computeR3Cached(newCachedBases(g1, h, g2, w), T3, g2, &values[3], w, &values[4], &values[5], &values[6], &values[3], values[4])
+    assert.True(t, expected.IsEqual(cached), "computeR3Cached should match computeR3")
+}
+
+// BenchmarkComputeR3 compares recomputing the pairings of R3 with applying exponents to cached base pairings.
+// The cached bases are computed once, outside the timed loop, as a batch verifier would.
+func BenchmarkComputeR3(b *testing.B) {
+    g1, T3, h, g2, w, values := r3Inputs()
+    bases := newCachedBases(g1, h, g2, w)
+
+    b.Run("Grouped", func(b *testing.B) {
+        for i := 0; i < b.N; i++ {
+            computeR3(T3, g1, g2, &values[3], h, w, &values[4], &values[5], &values[6], &values[3], values[4])
+        }
+    })
+    b.Run("CachedBases", func(b *testing.B) {
+        for i := 0; i < b.N; i++ {
+            computeR3Cached(bases, T3, g2, &values[3], w, &values[4], &values[5], &values[6], &values[3], values[4])
+        }
+    })
+}
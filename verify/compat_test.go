@@ -0,0 +1,40 @@
+package verify_test
+
+import (
+    "encoding/hex"
+    mathrand "math/rand"
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// legacySignature is a signature by user 1 of the group generated by KeyGenWithRand(2) with seed 853,
+// made over "Hello, world!" before the R values moved into internal/proof.
+const legacySignature = "b739946a150554f0cc988474b9db0981779b4a8101dbf908342bf29d8fff1f0918c73fb102d7e84d5cb0f405ae9025eb8131ed8ddb2388df97268e25ac60617bb2ca292078a71a53e918099a53a8c8b52b7ec58cf7a7bd2c4479262af2cba56c88d234deeb0749c9bdb184e38c3b848f8389082f8079bebd2ec3102039a41cfff034b75236fd85ae73addec444c954710bea7684176dece5e1526ba7d469a9d8fbd9be6e125b96dc1f2bec86a7f758e0557f8343644b18a2875e5e6de64f1839c3948be91f6cd9b7559670d03933eab75edb1aca9f9985be4514933c804e8a5c5e99f07e0bc29f267cc1bf36c3ea0c62481b60a12cd681d5219a9933495720f028d261686c6c6a417d0f1a6fa6bb92e70526a65699421eccfa8d8feda33e04f7a16316addf12e1eb563b4a2d33b2a55c3fe377d23148ef1ab589b32af4ee0387fe73adecf50ee8a5b68cf64dc33639c4"
+
+// TestVerifySharedProof tests that signatures verify with the R values shared by sign and verify,
+// including a signature made before they were shared.
+func TestVerifySharedProof(t *testing.T) {
+    result, err := keygen.KeyGenWithRand(2, mathrand.New(mathrand.NewSource(853)))
+    assert.NoError(t, err, "KeyGenWithRand should not return an error")
+
+    // A fresh signature verifies
+    signature, err := sign.Sign(result.PublicKey, result.Users[1], "Hello, world!")
+    assert.NoError(t, err, "Sign should not return an error")
+    valid, err := verify.Verify(result.PublicKey, "Hello, world!", signature)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.True(t, valid, "Verify should accept a signature made by Sign")
+
+    // A signature made before the refactor still verifies
+    data, err := hex.DecodeString(legacySignature)
+    assert.NoError(t, err, "DecodeString should not return an error")
+    var legacy models.Signature
+    assert.NoError(t, legacy.UnmarshalBinary(data), "UnmarshalBinary should not return an error")
+    valid, err = verify.Verify(result.PublicKey, "Hello, world!", legacy)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.True(t, valid, "Verify should accept a signature made before the refactor")
+}
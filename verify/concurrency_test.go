@@ -0,0 +1,65 @@
+package verify_test
+
+import (
+    "fmt"
+    "sync"
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestSharedPublicKey tests that many goroutines can sign and verify with one shared PublicKey.
+// Run it with -race to detect in-place mutation of the shared elements.
+func TestSharedPublicKey(t *testing.T) {
+    result, err := keygen.KeyGen(4)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    publicKey := result.PublicKey
+    before, err := publicKey.MarshalBinary()
+    assert.NoError(t, err, "MarshalBinary should not return an error")
+    prepared := sign.NewPreparedSigner(publicKey, result.Users[0])
+
+    const workers = 16
+    var wg sync.WaitGroup
+    errs := make(chan error, workers)
+    for i := 0; i < workers; i++ {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            message := fmt.Sprintf("message %d", i)
+
+            // Odd workers share one PreparedSigner, even workers sign as different users
+            var signature models.Signature
+            var err error
+            if i%2 == 0 {
+                signature, err = sign.Sign(publicKey, result.Users[i%len(result.Users)], message)
+            } else {
+                signature, err = prepared.Sign(message)
+            }
+            if err != nil {
+                errs <- err
+                return
+            }
+            valid, err := verify.Verify(publicKey, message, signature)
+            if err != nil {
+                errs <- err
+                return
+            }
+            if !valid {
+                errs <- fmt.Errorf("signature %d did not verify", i)
+            }
+        }(i)
+    }
+    wg.Wait()
+    close(errs)
+    for err := range errs {
+        assert.NoError(t, err, "Concurrent signing and verifying should succeed")
+    }
+
+    after, err := publicKey.MarshalBinary()
+    assert.NoError(t, err, "MarshalBinary should not return an error")
+    assert.Equal(t, before, after, "Signing and verifying should not modify the shared public key")
+}
@@ -0,0 +1,34 @@
+package verify_test
+
+import (
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestVerifyDetailed tests that VerifyDetailed returns the recomputed challenge.
+func TestVerifyDetailed(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    signature, err := sign.Sign(result.PublicKey, result.Users[0], "Hello, world!")
+    assert.NoError(t, err, "Sign should not return an error")
+
+    // For a valid signature the recomputed challenge equals C
+    valid, c, err := verify.VerifyDetailed(result.PublicKey, "Hello, world!", signature)
+    assert.NoError(t, err, "VerifyDetailed should not return an error")
+    assert.True(t, valid, "VerifyDetailed should accept a valid signature")
+    assert.Equal(t, 1, c.IsEqual(&signature.C), "the recomputed challenge should equal C")
+
+    // For a tampered signature it differs
+    tampered := signature
+    sx := *signature.SX
+    sx.Add(&sx, &signature.C)
+    tampered.SX = &sx
+    valid, c, err = verify.VerifyDetailed(result.PublicKey, "Hello, world!", tampered)
+    assert.NoError(t, err, "VerifyDetailed should not return an error")
+    assert.False(t, valid, "VerifyDetailed should reject a tampered signature")
+    assert.Equal(t, 0, c.IsEqual(&tampered.C), "the recomputed challenge should differ from C")
+}
@@ -0,0 +1,8 @@
+package verify
+
+import "github.com/aniagut/msc-bbs/models"
+
+// VerifyOwnedWithHook exposes verifyOwned, which calls afterClone once its copies are taken, to tests in package verify_test.
+func VerifyOwnedWithHook(publicKey models.PublicKey, M string, signature models.Signature, afterClone func()) (bool, error) {
+    return verifyOwned(publicKey, M, signature, afterClone, nil)
+}
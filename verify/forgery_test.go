@@ -0,0 +1,223 @@
+package verify_test
+
+import (
+    "testing"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/utils"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// forgery holds the values chosen by a forger who has no user key.
+// The forger knows alpha, beta and x, so R1, R2, R4 and R5 recompute correctly for any T3;
+// only R3 cannot be made to match, and the forger takes it to be 1.
+type forgery struct {
+    alpha, beta, x                      e.Scalar
+    rAlpha, rBeta, rX, rDelta1, rDelta2 e.Scalar
+    T3                                  *e.G1
+}
+
+// randomForgery draws all values of a forgery at random, with T3 set to the given point.
+func randomForgery(t *testing.T, T3 *e.G1) forgery {
+    scalars, err := sign.GenerateRandomScalars(8)
+    assert.NoError(t, err, "GenerateRandomScalars should not return an error")
+    return forgery{
+        alpha: scalars[0], beta: scalars[1], x: scalars[2],
+        rAlpha: scalars[3], rBeta: scalars[4], rX: scalars[5], rDelta1: scalars[6], rDelta2: scalars[7],
+        T3: T3,
+    }
+}
+
+// forge computes the signature described by f over the message, taking R3 = 1.
+// It verifies only if the verifier's R3 collapses to 1 as well.
+func forge(t *testing.T, publicKey models.PublicKey, message string, f forgery) models.Signature {
+    delta1, delta2 := sign.ComputeDeltas(f.alpha, f.beta, f.x)
+
+    T1 := new(e.G1)
+    T1.ScalarMult(&f.alpha, publicKey.U)
+    T2 := new(e.G1)
+    T2.ScalarMult(&f.beta, publicKey.V)
+
+    R3 := new(e.Gt)
+    R3.SetIdentity()
+    R1 := new(e.G1)
+    R1.ScalarMult(&f.rAlpha, publicKey.U)
+    R2 := new(e.G1)
+    R2.ScalarMult(&f.rBeta, publicKey.V)
+    R4 := sign.ComputeR4(T1, publicKey.U, f.rX, f.rDelta1)
+    R5 := sign.ComputeR5(T2, publicKey.V, f.rX, f.rDelta2)
+
+    digest := utils.DigestMessages([]string{message})
+    c, err := utils.SignatureTranscript(digest[:], T1, T2, f.T3, R1, R2, R3, R4, R5).Challenge()
+    assert.NoError(t, err, "Challenge should not return an error")
+    sAlpha, sBeta, sX, sDelta1, sDelta2 := sign.ComputeSValues(f.alpha, f.beta, f.x, delta1, delta2, f.rAlpha, f.rBeta, f.rX, f.rDelta1, f.rDelta2, c)
+
+    return models.Signature{T1: T1, T2: T2, T3: f.T3, C: c, SAlpha: sAlpha, SBeta: sBeta, SX: sX, SDelta1: sDelta1, SDelta2: sDelta2}
+}
+
+// zeroSXForgery returns a forgery with x = 0 and rX = 0, so that its sX = 0.
+func zeroSXForgery(t *testing.T, T3 *e.G1) forgery {
+    f := randomForgery(t, T3)
+    f.x.SetUint64(0)
+    f.rX.SetUint64(0)
+    return f
+}
+
+// zeroSumForgery returns a forgery with beta = -alpha and matching randomizers,
+// so that its sBeta = -sAlpha and sDelta2 = -sDelta1.
+func zeroSumForgery(t *testing.T, T3 *e.G1) forgery {
+    f := randomForgery(t, T3)
+    f.beta.Set(&f.alpha)
+    f.beta.Neg()
+    f.rBeta.Set(&f.rAlpha)
+    f.rBeta.Neg()
+    f.rDelta2.Set(&f.rDelta1)
+    f.rDelta2.Neg()
+    return f
+}
+
+// TestIdentityT3Forgery tests that a signature with T3 = O, made without any user key, is rejected.
+// If a pairing product with an identity argument collapsed to 1, R3 would no longer constrain the
+// signature and anyone could choose alpha, beta and x to satisfy the remaining equations.
+func TestIdentityT3Forgery(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    message := "forged"
+
+    T3 := new(e.G1)
+    T3.SetIdentity()
+    forged := forge(t, result.PublicKey, message, randomForgery(t, T3))
+
+    valid, err := verify.Verify(result.PublicKey, message, forged)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.False(t, valid, "A signature with T3 = O should not verify")
+}
+
+// TestZeroSXForgery tests that a signature with SX = 0, made without any user key, is rejected.
+// With x = 0 and rX = 0 the forger gets sX = 0; if the zero exponent collapsed the pairing
+// product to 1, R3 would no longer depend on T3 and any T3 would be accepted.
+func TestZeroSXForgery(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    message := "forged"
+
+    T3, err := utils.RandomG1Element()
+    assert.NoError(t, err, "RandomG1Element should not return an error")
+    forged := forge(t, result.PublicKey, message, zeroSXForgery(t, &T3))
+    assert.Equal(t, 1, forged.SX.IsZero(), "The forgery should have SX = 0")
+
+    valid, err := verify.Verify(result.PublicKey, message, forged)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.False(t, valid, "A signature with SX = 0 should not verify")
+}
+
+// TestZeroChallengeR3 tests that a zero challenge does not collapse the recomputed R3.
+// With C = 0 the challenge terms of R3 have zero exponents and must contribute 1,
+// leaving R3 = e(T3, g2)^sX * e(h, w)^(-sAlpha - sBeta) * e(h, g2)^(-sDelta1 - sDelta2).
+func TestZeroChallengeR3(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    publicKey := result.PublicKey
+
+    scalars, err := sign.GenerateRandomScalars(5)
+    assert.NoError(t, err, "GenerateRandomScalars should not return an error")
+    points := make([]*e.G1, 3)
+    for i := range points {
+        p, err := utils.RandomG1Element()
+        assert.NoError(t, err, "RandomG1Element should not return an error")
+        points[i] = &p
+    }
+    signature := models.Signature{
+        T1: points[0], T2: points[1], T3: points[2],
+        SAlpha: &scalars[0], SBeta: &scalars[1], SX: &scalars[2], SDelta1: &scalars[3], SDelta2: &scalars[4],
+    }
+
+    _, _, R3, _, _, err := verify.RecomputeRValues(publicKey, signature)
+    assert.NoError(t, err, "RecomputeRValues should not return an error")
+    expected, err := sign.ComputeR3(signature.T3, publicKey.G2, publicKey.H, publicKey.W, scalars[2], scalars[0], scalars[1], scalars[3], scalars[4])
+    assert.NoError(t, err, "ComputeR3 should not return an error")
+    assert.False(t, R3.IsIdentity(), "R3 should not collapse to 1 for C = 0")
+    assert.True(t, R3.IsEqual(expected), "The challenge terms should contribute 1 for C = 0")
+
+    valid, err := verify.Verify(publicKey, "forged", signature)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.False(t, valid, "A signature with C = 0 should not verify")
+}
+
+// TestZeroSumForgery tests that a signature with SBeta = -SAlpha and SDelta2 = -SDelta1 is rejected.
+// Every scalar is nonzero and every T-value is a regular point, so the canonical-form checks of
+// VerifyNonMalleable do not apply; the exponents of e(h, w) and e(h, g2) in R3 cancel to zero,
+// and R3 must still be computed from the remaining terms instead of collapsing to 1.
+func TestZeroSumForgery(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    message := "forged"
+
+    T3, err := utils.RandomG1Element()
+    assert.NoError(t, err, "RandomG1Element should not return an error")
+    forged := forge(t, result.PublicKey, message, zeroSumForgery(t, &T3))
+
+    sum := new(e.Scalar)
+    sum.Add(forged.SAlpha, forged.SBeta)
+    assert.Equal(t, 1, sum.IsZero(), "The forgery should have SBeta = -SAlpha")
+    sum.Add(forged.SDelta1, forged.SDelta2)
+    assert.Equal(t, 1, sum.IsZero(), "The forgery should have SDelta2 = -SDelta1")
+
+    valid, err := verify.Verify(result.PublicKey, message, forged)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.False(t, valid, "A zero-sum signature should not verify")
+
+    valid, err = verify.VerifyNonMalleable(result.PublicKey, message, forged)
+    assert.NoError(t, err, "The zero-sum signature is in canonical form")
+    assert.False(t, valid, "VerifyNonMalleable should reject a zero-sum signature")
+}
+
+// TestVerifyPathsAgree tests that Verify, VerifyAggregate and VerifyBatch, which evaluate R3 in
+// different forms, give the same answer for honest and crafted signatures.
+func TestVerifyPathsAgree(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    publicKey := result.PublicKey
+    message := "forged"
+
+    honest, err := sign.Sign(publicKey, result.Users[0], message)
+    assert.NoError(t, err, "Sign should not return an error")
+
+    identity := new(e.G1)
+    identity.SetIdentity()
+    identityT3 := forge(t, publicKey, message, randomForgery(t, identity))
+
+    T3, err := utils.RandomG1Element()
+    assert.NoError(t, err, "RandomG1Element should not return an error")
+
+    cases := []struct {
+        name      string
+        signature models.Signature
+        expected  bool
+    }{
+        {"honest", honest, true},
+        {"identity T3", identityT3, false},
+        {"zero SX", forge(t, publicKey, message, zeroSXForgery(t, &T3)), false},
+        {"zero sum", forge(t, publicKey, message, zeroSumForgery(t, &T3)), false},
+    }
+    for _, tc := range cases {
+        valid, err := verify.Verify(publicKey, message, tc.signature)
+        assert.NoError(t, err, "Verify should not return an error for %s", tc.name)
+        assert.Equal(t, tc.expected, valid, "Verify should return %v for %s", tc.expected, tc.name)
+
+        msgs, signatures := []string{message}, []models.Signature{tc.signature}
+        valid, err = verify.VerifyAggregate(publicKey, msgs, signatures)
+        assert.NoError(t, err, "VerifyAggregate should not return an error for %s", tc.name)
+        assert.Equal(t, tc.expected, valid, "VerifyAggregate should agree with Verify for %s", tc.name)
+
+        for _, mode := range []verify.BatchMode{verify.ModeAllOrNothing, verify.ModePerSignature} {
+            batch, err := verify.VerifyBatch(publicKey, msgs, signatures, mode)
+            assert.NoError(t, err, "VerifyBatch should not return an error for %s", tc.name)
+            assert.Equal(t, tc.expected, batch.Valid, "VerifyBatch should agree with Verify for %s in mode %d", tc.name, mode)
+        }
+    }
+}
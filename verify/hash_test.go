@@ -0,0 +1,69 @@
+package verify_test
+
+import (
+    "crypto/sha256"
+    "crypto/sha512"
+    "errors"
+    "hash"
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/utils"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestVerifyWithHash tests that signer and verifier must agree on the challenge hash.
+func TestVerifyWithHash(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    message := "Hello, world!"
+
+    // A SHA-512 signature verifies only under SHA-512
+    signature, err := sign.Sign(result.PublicKey, result.Users[0], message, utils.WithHash(sha512.New))
+    assert.NoError(t, err, "Sign should not return an error")
+    valid, err := verify.Verify(result.PublicKey, message, signature, utils.WithHash(sha512.New))
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.True(t, valid, "Verify should accept a signature under the same hash")
+    valid, err = verify.Verify(result.PublicKey, message, signature, utils.WithHash(sha256.New))
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.False(t, valid, "Verify should reject a signature under a different hash")
+    valid, err = verify.Verify(result.PublicKey, message, signature)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.False(t, valid, "Verify should reject a SHA-512 signature under the default hash")
+
+    // The default is SHA-256
+    signature, err = sign.Sign(result.PublicKey, result.Users[0], message)
+    assert.NoError(t, err, "Sign should not return an error")
+    valid, err = verify.Verify(result.PublicKey, message, signature, utils.WithHash(sha256.New))
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.True(t, valid, "Verify should accept a default signature under SHA-256")
+}
+
+// failingHash is a hash function whose Write always fails.
+type failingHash struct {
+    hash.Hash
+}
+
+func (failingHash) Write(p []byte) (int, error) {
+    return 0, errors.New("hash unavailable")
+}
+
+// TestHashFailure tests that a failing challenge hash reaches the caller of Sign and Verify as utils.ErrHashFailure.
+func TestHashFailure(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    failing := utils.WithHash(func() hash.Hash { return failingHash{sha256.New()} })
+
+    _, err = sign.Sign(result.PublicKey, result.Users[0], "Hello, world!", failing)
+    assert.ErrorIs(t, err, utils.ErrHashFailure, "Sign should wrap ErrHashFailure")
+
+    signature, err := sign.Sign(result.PublicKey, result.Users[0], "Hello, world!")
+    assert.NoError(t, err, "Sign should not return an error")
+    valid, err := verify.Verify(result.PublicKey, "Hello, world!", signature, failing)
+    assert.ErrorIs(t, err, utils.ErrHashFailure, "Verify should wrap ErrHashFailure")
+    assert.False(t, errors.Is(err, utils.ErrRandomnessFailure), "A hash failure should not look like a randomness failure")
+    assert.False(t, valid, "Verify should not accept a signature it could not check")
+}
@@ -0,0 +1,59 @@
+package verify
+
+import (
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// VerifyLinkable checks the validity of a linkable BBS signature and of its tag for the given epoch.
+//
+// Parameters:
+//   - publicKey: The public key of the system (gpk = (g1, g2, h, u, v, w)).
+//   - M: The message being verified.
+//   - epoch: The epoch the signature was made in.
+//   - signature: The linkable signature to verify.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - bool: True if the signature and the tag are valid, false otherwise.
+//   - error: An error if the verification process fails.
+func VerifyLinkable(publicKey models.PublicKey, M string, epoch []byte, signature models.LinkableSignature, opts ...utils.HashOption) (bool, error) {
+    if signature.Tag == nil {
+        return false, nil
+    }
+
+    // Recompute R6 = H(epoch)^{s_x} * tag^{-c}
+    base := utils.LinkBase(epoch)
+    extend := func(t *utils.Transcript, s models.Signature) {
+        R6 := new(e.G1)
+        R6.ScalarMult(s.SX, base)
+
+        minusC := new(e.Scalar)
+        minusC.Set(&s.C)
+        minusC.Neg()
+        tagMinusC := new(e.G1)
+        tagMinusC.ScalarMult(minusC, signature.Tag)
+        R6.Add(R6, tagMinusC)
+
+        t.AppendMessage("epoch", epoch)
+        t.AppendG1("tag", signature.Tag)
+        t.AppendG1("R6", R6)
+    }
+    return verifyWithExtension(publicKey, utils.DigestMessages([]string{M}), signature.Signature, extend, opts, nil)
+}
+
+// LinkTag returns the linkability tag of a signature.
+func LinkTag(signature models.LinkableSignature) *e.G1 {
+    return signature.Tag
+}
+
+// Linked reports whether two linkable signatures carry the same tag,
+// i.e. were made by the same member in the same epoch.
+// Both signatures should have been verified with VerifyLinkable first.
+func Linked(sigA, sigB models.LinkableSignature) bool {
+    if sigA.Tag == nil || sigB.Tag == nil {
+        return false
+    }
+    return sigA.Tag.IsEqual(sigB.Tag)
+}
@@ -0,0 +1,61 @@
+package verify_test
+
+import (
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// signLinkable signs and verifies a linkable signature.
+func signLinkable(t *testing.T, result models.KeyGenResult, user int, m string, epoch []byte) models.LinkableSignature {
+    signature, err := sign.SignLinkable(result.PublicKey, result.Users[user], m, epoch)
+    assert.NoError(t, err, "SignLinkable should not return an error")
+    valid, err := verify.VerifyLinkable(result.PublicKey, m, epoch, signature)
+    assert.NoError(t, err, "VerifyLinkable should not return an error")
+    assert.True(t, valid, "VerifyLinkable should accept a valid linkable signature")
+    return signature
+}
+
+// TestLinked tests the linkability of signatures across users and epochs.
+func TestLinked(t *testing.T) {
+    result, err := keygen.KeyGen(2)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    epoch1, epoch2 := []byte("2026-10-14"), []byte("2026-10-15")
+    a := signLinkable(t, result, 0, "first", epoch1)
+    b := signLinkable(t, result, 0, "second", epoch1)
+    c := signLinkable(t, result, 0, "third", epoch2)
+    d := signLinkable(t, result, 1, "fourth", epoch1)
+
+    assert.True(t, verify.Linked(a, b), "Same user in the same epoch should be linked")
+    assert.False(t, verify.Linked(a, c), "Same user in different epochs should not be linked")
+    assert.False(t, verify.Linked(a, d), "Different users should not be linked")
+    assert.True(t, verify.LinkTag(a).IsEqual(verify.LinkTag(b)), "LinkTag should return the shared tag")
+}
+
+// TestVerifyLinkableForgedTag tests that a tag not matching the signer's key is rejected.
+func TestVerifyLinkableForgedTag(t *testing.T) {
+    result, err := keygen.KeyGen(2)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    epoch := []byte("2026-10-14")
+    signature, err := sign.SignLinkable(result.PublicKey, result.Users[0], "message", epoch)
+    assert.NoError(t, err, "SignLinkable should not return an error")
+    other, err := sign.SignLinkable(result.PublicKey, result.Users[1], "message", epoch)
+    assert.NoError(t, err, "SignLinkable should not return an error")
+
+    // Swap in another user's tag
+    signature.Tag = other.Tag
+    valid, err := verify.VerifyLinkable(result.PublicKey, "message", epoch, signature)
+    assert.NoError(t, err, "VerifyLinkable should not return an error")
+    assert.False(t, valid, "VerifyLinkable should reject a forged tag")
+
+    // Verify under a different epoch
+    valid, err = verify.VerifyLinkable(result.PublicKey, "message", []byte("2026-10-15"), other)
+    assert.NoError(t, err, "VerifyLinkable should not return an error")
+    assert.False(t, valid, "VerifyLinkable should reject a signature for a different epoch")
+}
@@ -0,0 +1,44 @@
+package verify
+
+import (
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// LogVerifier verifies checkpoints of an append-only log signed by a sign.LogSigner.
+// It must be fed the same entries in the same order as the signer; a missing, altered
+// or reordered entry changes the running hash and fails every later checkpoint.
+// A LogVerifier is not safe for concurrent use.
+type LogVerifier struct {
+    digest *utils.LogDigest
+}
+
+// NewLogVerifier creates a LogVerifier for an empty log.
+//
+// Returns:
+//   - *LogVerifier: The log verifier.
+func NewLogVerifier() *LogVerifier {
+    return &LogVerifier{digest: utils.NewLogDigest()}
+}
+
+// Append adds an entry to the log.
+//
+// Parameters:
+//   - entry: The entry to be appended.
+func (l *LogVerifier) Append(entry []byte) {
+    l.digest.Append(entry)
+}
+
+// VerifyCheckpoint checks a checkpoint signature over all entries appended so far.
+//
+// Parameters:
+//   - publicKey: The public key of the system (gpk = (g1, g2, h, u, v, w)).
+//   - signature: The checkpoint signature to verify.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - bool: True if the signature is valid for the entries appended so far, false otherwise.
+//   - error: An error if the verification process fails.
+func (l *LogVerifier) VerifyCheckpoint(publicKey models.PublicKey, signature models.Signature, opts ...utils.HashOption) (bool, error) {
+    return verifyDigest(publicKey, l.digest.Sum(), signature, opts)
+}
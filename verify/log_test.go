@@ -0,0 +1,67 @@
+package verify_test
+
+import (
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestVerifyCheckpoint tests that checkpoints of a growing log verify against the same entry stream.
+func TestVerifyCheckpoint(t *testing.T) {
+    result, err := keygen.KeyGen(2)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    signer := sign.NewLogSigner()
+    verifier := verify.NewLogVerifier()
+    for _, entry := range []string{"login anna", "read report", "logout anna"} {
+        signer.Append([]byte(entry))
+        verifier.Append([]byte(entry))
+    }
+
+    signature, digest, err := signer.SignCheckpoint(result.PublicKey, result.Users[0])
+    assert.NoError(t, err, "SignCheckpoint should not return an error")
+    assert.Len(t, digest, 32, "SignCheckpoint should return the 32-byte checkpoint digest")
+
+    valid, err := verifier.VerifyCheckpoint(result.PublicKey, signature)
+    assert.NoError(t, err, "VerifyCheckpoint should not return an error")
+    assert.True(t, valid, "VerifyCheckpoint should accept a checkpoint over the same entries")
+
+    // A later checkpoint covers the entries appended since
+    signer.Append([]byte("login gut"))
+    verifier.Append([]byte("login gut"))
+    later, _, err := signer.SignCheckpoint(result.PublicKey, result.Users[0])
+    assert.NoError(t, err, "SignCheckpoint should not return an error")
+    valid, err = verifier.VerifyCheckpoint(result.PublicKey, later)
+    assert.NoError(t, err, "VerifyCheckpoint should not return an error")
+    assert.True(t, valid, "VerifyCheckpoint should accept a later checkpoint")
+
+    // The earlier checkpoint no longer matches the longer log
+    valid, err = verifier.VerifyCheckpoint(result.PublicKey, signature)
+    assert.NoError(t, err, "VerifyCheckpoint should not return an error")
+    assert.False(t, valid, "VerifyCheckpoint should reject a checkpoint over fewer entries")
+}
+
+// TestVerifyCheckpointMissingEntry tests that a verifier missing one entry rejects the checkpoint.
+func TestVerifyCheckpointMissingEntry(t *testing.T) {
+    result, err := keygen.KeyGen(2)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    signer := sign.NewLogSigner()
+    verifier := verify.NewLogVerifier()
+    for i, entry := range []string{"login anna", "read report", "logout anna"} {
+        signer.Append([]byte(entry))
+        if i != 1 {
+            verifier.Append([]byte(entry))
+        }
+    }
+
+    signature, _, err := signer.SignCheckpoint(result.PublicKey, result.Users[0])
+    assert.NoError(t, err, "SignCheckpoint should not return an error")
+
+    valid, err := verifier.VerifyCheckpoint(result.PublicKey, signature)
+    assert.NoError(t, err, "VerifyCheckpoint should not return an error")
+    assert.False(t, valid, "VerifyCheckpoint should reject a log with a missing entry")
+}
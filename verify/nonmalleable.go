@@ -0,0 +1,80 @@
+package verify
+
+import (
+    "errors"
+    "fmt"
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// ErrNonCanonicalSignature is returned by VerifyNonMalleable for signatures that are not in canonical form.
+var ErrNonCanonicalSignature = errors.New("non-canonical signature")
+
+// VerifyNonMalleable checks the validity of a BBS signature and additionally enforces
+// that the signature is in canonical form.
+//
+// The challenge c binds the message, the commitments T1, T2, T3 and the recomputed R values,
+// so transforming the s-values (e.g. negating them) changes the R values and therefore the
+// challenge, unless a hash collision is found. Group elements are hashed in their affine
+// encoding, which is unique for every point. On top of Verify, signatures with identity
+// T-values or zero scalars, which an honest signer produces only with negligible probability,
+// are rejected as non-canonical before the regular verification runs.
+//
+// These checks are a policy, not what makes verification sound: Verify alone rejects forgeries
+// built from degenerate values, including ones these checks do not catch, such as
+// SBeta = -SAlpha, because utils.MultiPair evaluates R3 without the pairing terms that are 1
+// instead of collapsing the whole product.
+//
+// Parameters:
+//   - publicKey: The public key of the system (gpk = (g1, g2, h, u, v, w)).
+//   - M: The message being verified.
+//   - signature: The BBS signature to verify.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - bool: True if the signature is valid and canonical, false otherwise.
+//   - error: An error describing why the signature is not canonical, or if the verification process fails.
+func VerifyNonMalleable(publicKey models.PublicKey, M string, signature models.Signature, opts ...utils.HashOption) (bool, error) {
+    if err := checkCanonicalForm(signature); err != nil {
+        return false, err
+    }
+    return Verify(publicKey, M, signature, opts...)
+}
+
+// checkCanonicalForm rejects signatures with identity T-values or zero challenge and response scalars.
+func checkCanonicalForm(signature models.Signature) error {
+    // Check that T1, T2, T3 are non-identity elements of G1
+    points := []struct {
+        name  string
+        value *e.G1
+    }{
+        {"T1", signature.T1},
+        {"T2", signature.T2},
+        {"T3", signature.T3},
+    }
+    for _, p := range points {
+        if p.value == nil || p.value.IsIdentity() || !p.value.IsOnG1() {
+            return fmt.Errorf("%w: %s must be a non-identity element of G1", ErrNonCanonicalSignature, p.name)
+        }
+    }
+
+    // Check that the challenge and the s-values are nonzero
+    scalars := []struct {
+        name  string
+        value *e.Scalar
+    }{
+        {"C", &signature.C},
+        {"SAlpha", signature.SAlpha},
+        {"SBeta", signature.SBeta},
+        {"SX", signature.SX},
+        {"SDelta1", signature.SDelta1},
+        {"SDelta2", signature.SDelta2},
+    }
+    for _, s := range scalars {
+        if s.value == nil || s.value.IsZero() == 1 {
+            return fmt.Errorf("%w: %s must be a nonzero scalar", ErrNonCanonicalSignature, s.name)
+        }
+    }
+    return nil
+}
@@ -0,0 +1,67 @@
+package verify_test
+
+import (
+    "testing"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestVerifyNonMalleable tests that VerifyNonMalleable accepts a valid signature
+// and rejects malleated versions of it.
+func TestVerifyNonMalleable(t *testing.T) {
+    result, err := keygen.KeyGen(2)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    message := "Hello, world!"
+    signature, err := sign.Sign(result.PublicKey, result.Users[0], message)
+    assert.NoError(t, err, "Sign should not return an error")
+
+    // The original signature is valid and canonical
+    valid, err := verify.VerifyNonMalleable(result.PublicKey, message, signature)
+    assert.NoError(t, err, "VerifyNonMalleable should not return an error for a valid signature")
+    assert.True(t, valid, "VerifyNonMalleable should accept a valid signature")
+
+    // Negate all s-values
+    negated := signature
+    negated.SAlpha, negated.SBeta, negated.SX, negated.SDelta1, negated.SDelta2 =
+        negate(signature.SAlpha), negate(signature.SBeta), negate(signature.SX), negate(signature.SDelta1), negate(signature.SDelta2)
+    valid, err = verify.VerifyNonMalleable(result.PublicKey, message, negated)
+    assert.NoError(t, err, "Negated s-values are nonzero and should reach the challenge check")
+    assert.False(t, valid, "VerifyNonMalleable should reject a signature with negated s-values")
+
+    // Negate T1 together with sAlpha
+    negatedT1 := signature
+    negatedT1.T1 = new(e.G1)
+    *negatedT1.T1 = *signature.T1
+    negatedT1.T1.Neg()
+    negatedT1.SAlpha = negate(signature.SAlpha)
+    valid, _ = verify.VerifyNonMalleable(result.PublicKey, message, negatedT1)
+    assert.False(t, valid, "VerifyNonMalleable should reject a signature with negated T1 and sAlpha")
+
+    // Replace T2 with the identity element
+    identityT2 := signature
+    identityT2.T2 = new(e.G1)
+    identityT2.T2.SetIdentity()
+    valid, err = verify.VerifyNonMalleable(result.PublicKey, message, identityT2)
+    assert.ErrorIs(t, err, verify.ErrNonCanonicalSignature, "VerifyNonMalleable should return an error for an identity T-value")
+    assert.False(t, valid, "VerifyNonMalleable should reject an identity T-value")
+
+    // Replace sX with zero
+    zeroSX := signature
+    zeroSX.SX = new(e.Scalar)
+    valid, err = verify.VerifyNonMalleable(result.PublicKey, message, zeroSX)
+    assert.ErrorIs(t, err, verify.ErrNonCanonicalSignature, "VerifyNonMalleable should return an error for a zero s-value")
+    assert.False(t, valid, "VerifyNonMalleable should reject a zero s-value")
+}
+
+// negate returns a new scalar equal to -s.
+func negate(s *e.Scalar) *e.Scalar {
+    n := new(e.Scalar)
+    n.Set(s)
+    n.Neg()
+    return n
+}
@@ -0,0 +1,31 @@
+package verify
+
+import (
+    "log/slog"
+
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// VerifyOptions configures optional behaviour of VerifyWithOptions.
+type VerifyOptions struct {
+    // Logger receives the recomputed R values, the recomputed challenge c and the result
+    // of the comparison at debug level. Nothing is logged if it is nil.
+    Logger *slog.Logger
+}
+
+// VerifyWithOptions checks the validity of a BBS signature like Verify, with the given options applied.
+//
+// Parameters:
+//   - publicKey: The public key of the system (gpk = (g1, g2, h, u, v, w)).
+//   - M: The message being verified.
+//   - signature: The BBS signature to verify.
+//   - options: The verification options, e.g. a logger for diagnostics.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - bool: True if the signature is valid, false otherwise.
+//   - error: ErrMalformedSignature if a field of the signature is missing, or an error if the verification process fails.
+func VerifyWithOptions(publicKey models.PublicKey, M string, signature models.Signature, options VerifyOptions, opts ...utils.HashOption) (bool, error) {
+    return verifyWithExtension(publicKey, utils.DigestMessages([]string{M}), signature, nil, opts, options.Logger)
+}
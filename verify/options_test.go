@@ -0,0 +1,68 @@
+package verify_test
+
+import (
+    "bytes"
+    "io"
+    "log/slog"
+    "os"
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestVerifyWithOptionsLogger tests that a configured logger receives the debug records.
+func TestVerifyWithOptionsLogger(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    signature, err := sign.Sign(result.PublicKey, result.Users[0], "Hello, world!")
+    assert.NoError(t, err, "Sign should not return an error")
+
+    var buf bytes.Buffer
+    logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+    valid, err := verify.VerifyWithOptions(result.PublicKey, "Hello, world!", signature, verify.VerifyOptions{Logger: logger})
+    assert.NoError(t, err, "VerifyWithOptions should not return an error")
+    assert.True(t, valid, "VerifyWithOptions should accept a valid signature")
+
+    output := buf.String()
+    assert.Contains(t, output, "level=DEBUG", "records should be logged at debug level")
+    assert.Contains(t, output, "recomputed R values", "the R values should be logged")
+    assert.Contains(t, output, "R3=", "the R values should be logged")
+    assert.Contains(t, output, "compared challenges", "the challenge comparison should be logged")
+    assert.Contains(t, output, "equal=true", "the comparison result should be logged")
+}
+
+// TestVerifySilentByDefault tests that verification logs and prints nothing without a logger.
+func TestVerifySilentByDefault(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    signature, err := sign.Sign(result.PublicKey, result.Users[0], "Hello, world!")
+    assert.NoError(t, err, "Sign should not return an error")
+
+    // Capture the default logger and stdout
+    var buf bytes.Buffer
+    previous := slog.Default()
+    slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+    defer slog.SetDefault(previous)
+
+    stdout := os.Stdout
+    reader, writer, err := os.Pipe()
+    assert.NoError(t, err, "Pipe should not return an error")
+    os.Stdout = writer
+
+    valid, verifyErr := verify.VerifyWithOptions(result.PublicKey, "Hello, world!", signature, verify.VerifyOptions{})
+    _, _ = verify.Verify(result.PublicKey, "Hello, world!", signature)
+
+    os.Stdout = stdout
+    writer.Close()
+    printed, err := io.ReadAll(reader)
+    assert.NoError(t, err, "ReadAll should not return an error")
+
+    assert.NoError(t, verifyErr, "VerifyWithOptions should not return an error")
+    assert.True(t, valid, "VerifyWithOptions should accept a valid signature")
+    assert.Empty(t, buf.String(), "nothing should be logged without a logger")
+    assert.Empty(t, string(printed), "nothing should be printed to stdout")
+}
@@ -0,0 +1,37 @@
+package verify
+
+import (
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// VerifyOwned checks the validity of a BBS signature like Verify, working on deep copies of the
+// public key and the signature made with Clone. Once the copies are taken, the verification no longer
+// reads the caller's elements, so the caller may modify or reuse them while it is still running.
+// The copies themselves read the elements, so the caller must not modify them before VerifyOwned is called.
+//
+// Cloning copies nine group elements and six scalars, about one kilobyte, which is negligible
+// next to the pairings of the verification itself.
+//
+// Parameters:
+//   - publicKey: The public key of the system (gpk = (g1, g2, h, u, v, w)).
+//   - M: The message being verified.
+//   - signature: The BBS signature to verify.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - bool: True if the signature is valid, false otherwise.
+//   - error: ErrMalformedSignature if a field of the signature is missing, or an error if the verification process fails.
+func VerifyOwned(publicKey models.PublicKey, M string, signature models.Signature, opts ...utils.HashOption) (bool, error) {
+    return verifyOwned(publicKey, M, signature, nil, opts)
+}
+
+// verifyOwned implements VerifyOwned. If afterClone is not nil, it is called once the copies are
+// taken; tests use it to mutate the caller's elements while the verification is still running.
+func verifyOwned(publicKey models.PublicKey, M string, signature models.Signature, afterClone func(), opts []utils.HashOption) (bool, error) {
+    ownedKey, ownedSignature := publicKey.Clone(), signature.Clone()
+    if afterClone != nil {
+        afterClone()
+    }
+    return Verify(ownedKey, M, ownedSignature, opts...)
+}
@@ -0,0 +1,48 @@
+package verify_test
+
+import (
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestVerifyOwned tests that mutating the caller's public key while VerifyOwned runs does not affect it.
+// Run it with -race to confirm that the verification no longer reads the caller's elements.
+func TestVerifyOwned(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    message := "Hello, world!"
+    signature, err := sign.Sign(result.PublicKey, result.Users[0], message)
+    assert.NoError(t, err, "Sign should not return an error")
+
+    // Hold the caller back until VerifyOwned has taken its copies
+    cloned := make(chan struct{})
+    mutated := make(chan struct{})
+    afterClone := func() {
+        close(cloned)
+        <-mutated
+    }
+
+    done := make(chan bool)
+    go func() {
+        valid, err := verify.VerifyOwnedWithHook(result.PublicKey, message, signature, afterClone)
+        assert.NoError(t, err, "VerifyOwned should not return an error")
+        done <- valid
+    }()
+
+    // Mutate the caller's elements in place while the verification is in progress
+    <-cloned
+    result.PublicKey.H.Double()
+    result.PublicKey.W.Double()
+    signature.T3.Double()
+    signature.SX.SetUint64(1)
+    close(mutated)
+
+    assert.True(t, <-done, "VerifyOwned should verify the key and signature as they were when called")
+    valid, err := verify.Verify(result.PublicKey, message, signature)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.False(t, valid, "The mutated key and signature should no longer verify")
+}
@@ -0,0 +1,38 @@
+package verify_test
+
+import (
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/utils"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestVerifyPairingCount tests that a single Verify computes two pairings with one final exponentiation.
+func TestVerifyPairingCount(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    signature, err := sign.Sign(result.PublicKey, result.Users[0], "Hello, world!")
+    assert.NoError(t, err, "Sign should not return an error")
+
+    utils.Pairings.Enable()
+    defer utils.Pairings.Disable()
+    utils.Pairings.Reset()
+
+    valid, err := verify.Verify(result.PublicKey, "Hello, world!", signature)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.True(t, valid, "Verify should accept a valid signature")
+
+    // R3 = e(T3^sx * h^(-sδ1-sδ2) * g1^(-c), g2) * e(h^(-sα-sβ) * T3^c, w)
+    assert.Equal(t, int64(2), utils.Pairings.MillerLoops(), "Verify should compute two pairings")
+    assert.Equal(t, int64(1), utils.Pairings.FinalExponentiations(), "Verify should compute one final exponentiation")
+
+    // Nothing is counted while the counter is disabled
+    utils.Pairings.Disable()
+    utils.Pairings.Reset()
+    _, err = verify.Verify(result.PublicKey, "Hello, world!", signature)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.Equal(t, int64(0), utils.Pairings.MillerLoops(), "a disabled counter should not count")
+}
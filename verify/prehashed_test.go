@@ -0,0 +1,40 @@
+package verify_test
+
+import (
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/utils"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestVerifyPrehashed tests that SignPrehashed(H(m)) and Sign(m) agree.
+func TestVerifyPrehashed(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    message := "Hello, world!"
+    digest := utils.DigestMessages([]string{message})
+
+    // A signature over the digest verifies as a signature over the message
+    signature, err := sign.SignPrehashed(result.PublicKey, result.Users[0], digest)
+    assert.NoError(t, err, "SignPrehashed should not return an error")
+    valid, err := verify.Verify(result.PublicKey, message, signature)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.True(t, valid, "Verify should accept a signature over the matching digest")
+
+    // A signature over the message verifies as a signature over the digest
+    signature, err = sign.Sign(result.PublicKey, result.Users[0], message)
+    assert.NoError(t, err, "Sign should not return an error")
+    valid, err = verify.VerifyPrehashed(result.PublicKey, digest, signature)
+    assert.NoError(t, err, "VerifyPrehashed should not return an error")
+    assert.True(t, valid, "VerifyPrehashed should accept a signature over the matching message")
+
+    // A different digest does not verify
+    digest[0] ^= 0xff
+    valid, err = verify.VerifyPrehashed(result.PublicKey, digest, signature)
+    assert.NoError(t, err, "VerifyPrehashed should not return an error")
+    assert.False(t, valid, "VerifyPrehashed should reject a different digest")
+}
@@ -0,0 +1,85 @@
+package verify
+
+import (
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// FailureReason tells why VerifyWithReason rejected a signature.
+type FailureReason int
+
+const (
+    // ReasonOK means the signature is valid.
+    ReasonOK FailureReason = iota
+    // ReasonMalformedPublicKey means an element of the public key is missing.
+    ReasonMalformedPublicKey
+    // ReasonMalformedSignature means a field of the signature is missing.
+    ReasonMalformedSignature
+    // ReasonMalformedPoint means T1, T2 or T3 is the identity or outside the prime-order subgroup of G1.
+    ReasonMalformedPoint
+    // ReasonChallengeMismatch means the signature is well formed but the recomputed challenge differs from C,
+    // e.g. because the message, the public key or a response does not match.
+    ReasonChallengeMismatch
+    // ReasonInternalError means the signature could not be checked, e.g. because the challenge hash failed.
+    // It is the only reason returned together with a non-nil error.
+    ReasonInternalError
+)
+
+// String returns the name of the reason, e.g. for logs and metrics labels.
+func (r FailureReason) String() string {
+    switch r {
+    case ReasonOK:
+        return "ok"
+    case ReasonMalformedPublicKey:
+        return "malformed public key"
+    case ReasonMalformedSignature:
+        return "malformed signature"
+    case ReasonMalformedPoint:
+        return "malformed point"
+    case ReasonChallengeMismatch:
+        return "challenge mismatch"
+    case ReasonInternalError:
+        return "internal error"
+    default:
+        return "unknown"
+    }
+}
+
+// VerifyWithReason checks the validity of a BBS signature like Verify and reports why it is rejected.
+// Unlike Verify, problems with the inputs are reported as a reason and not as an error,
+// so every rejected signature yields false, a reason other than ReasonOK and a nil error.
+// Only a failure of the verification process itself is returned as an error, with ReasonInternalError.
+//
+// Parameters:
+//   - publicKey: The public key of the system (gpk = (g1, g2, h, u, v, w)).
+//   - M: The message being verified.
+//   - signature: The BBS signature to verify.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - bool: True if the signature is valid, false otherwise.
+//   - FailureReason: ReasonOK for a valid signature, the reason it was rejected, or ReasonInternalError.
+//   - error: An error if the verification process itself fails, e.g. the challenge cannot be hashed.
+func VerifyWithReason(publicKey models.PublicKey, M string, signature models.Signature, opts ...utils.HashOption) (bool, FailureReason, error) {
+    if publicKey.G1 == nil || publicKey.G2 == nil || publicKey.H == nil || publicKey.U == nil || publicKey.V == nil || publicKey.W == nil {
+        return false, ReasonMalformedPublicKey, nil
+    }
+    if validateSignatureShape(signature) != nil {
+        return false, ReasonMalformedSignature, nil
+    }
+    for _, T := range []*e.G1{signature.T1, signature.T2, signature.T3} {
+        if T.IsIdentity() || !T.IsOnG1() {
+            return false, ReasonMalformedPoint, nil
+        }
+    }
+
+    valid, _, err := verifyDetailed(publicKey, utils.DigestMessages([]string{M}), signature, nil, opts, nil)
+    if err != nil {
+        return false, ReasonInternalError, err
+    }
+    if !valid {
+        return false, ReasonChallengeMismatch, nil
+    }
+    return true, ReasonOK, nil
+}
@@ -0,0 +1,84 @@
+package verify_test
+
+import (
+    "crypto/sha256"
+    "hash"
+    "testing"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/utils"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestVerifyWithReason tests that each kind of rejection is reported with its own reason.
+func TestVerifyWithReason(t *testing.T) {
+    result, err := keygen.KeyGen(2)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    message := "Hello, world!"
+    signature, err := sign.Sign(result.PublicKey, result.Users[0], message)
+    assert.NoError(t, err, "Sign should not return an error")
+
+    identity := new(e.G1)
+    identity.SetIdentity()
+
+    missingW := result.PublicKey
+    missingW.W = nil
+    missingSX := signature
+    missingSX.SX = nil
+    identityT3 := signature
+    identityT3.T3 = identity
+    otherKey, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    cases := []struct {
+        name   string
+        valid  bool
+        reason verify.FailureReason
+        run    func() (bool, verify.FailureReason, error)
+    }{
+        {"valid", true, verify.ReasonOK, func() (bool, verify.FailureReason, error) {
+            return verify.VerifyWithReason(result.PublicKey, message, signature)
+        }},
+        {"missing public key element", false, verify.ReasonMalformedPublicKey, func() (bool, verify.FailureReason, error) {
+            return verify.VerifyWithReason(missingW, message, signature)
+        }},
+        {"missing response", false, verify.ReasonMalformedSignature, func() (bool, verify.FailureReason, error) {
+            return verify.VerifyWithReason(result.PublicKey, message, missingSX)
+        }},
+        {"identity T3", false, verify.ReasonMalformedPoint, func() (bool, verify.FailureReason, error) {
+            return verify.VerifyWithReason(result.PublicKey, message, identityT3)
+        }},
+        {"wrong message", false, verify.ReasonChallengeMismatch, func() (bool, verify.FailureReason, error) {
+            return verify.VerifyWithReason(result.PublicKey, "tampered", signature)
+        }},
+        {"wrong public key", false, verify.ReasonChallengeMismatch, func() (bool, verify.FailureReason, error) {
+            return verify.VerifyWithReason(otherKey.PublicKey, message, signature)
+        }},
+    }
+    for _, c := range cases {
+        valid, reason, err := c.run()
+        assert.NoError(t, err, "%s: VerifyWithReason should not return an error", c.name)
+        assert.Equal(t, c.valid, valid, "%s: unexpected validity", c.name)
+        assert.Equal(t, c.reason, reason, "%s: expected reason %s, got %s", c.name, c.reason, reason)
+    }
+    assert.Equal(t, "challenge mismatch", verify.ReasonChallengeMismatch.String(), "Reasons should have readable names")
+}
+
+// TestVerifyWithReasonInternalError tests that a failure of the verification process is reported
+// as ReasonInternalError together with the error.
+func TestVerifyWithReasonInternalError(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    signature, err := sign.Sign(result.PublicKey, result.Users[0], "Hello, world!")
+    assert.NoError(t, err, "Sign should not return an error")
+
+    failing := utils.WithHash(func() hash.Hash { return failingHash{sha256.New()} })
+    valid, reason, err := verify.VerifyWithReason(result.PublicKey, "Hello, world!", signature, failing)
+    assert.ErrorIs(t, err, utils.ErrHashFailure, "VerifyWithReason should wrap ErrHashFailure")
+    assert.False(t, valid, "VerifyWithReason should not accept a signature it could not check")
+    assert.Equal(t, verify.ReasonInternalError, reason, "expected reason %s, got %s", verify.ReasonInternalError, reason)
+    assert.Equal(t, "internal error", reason.String(), "Reasons should have readable names")
+}
@@ -0,0 +1,34 @@
+package verify_test
+
+import (
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestVerifyRecord tests that a signed record verifies after its keys are inserted in a different order.
+func TestVerifyRecord(t *testing.T) {
+    result, err := keygen.KeyGen(2)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    record := map[string]string{"name": "Anna", "surname": "Gut", "born": "1999-01-01"}
+    signature, err := sign.SignRecord(result.PublicKey, result.Users[0], record)
+    assert.NoError(t, err, "SignRecord should not return an error")
+
+    reordered := map[string]string{}
+    reordered["born"] = "1999-01-01"
+    reordered["surname"] = "Gut"
+    reordered["name"] = "Anna"
+    valid, err := verify.VerifyRecord(result.PublicKey, reordered, signature)
+    assert.NoError(t, err, "VerifyRecord should not return an error")
+    assert.True(t, valid, "VerifyRecord should accept the same record in a different order")
+
+    // Changing a value must invalidate the signature
+    reordered["born"] = "2000-01-01"
+    valid, err = verify.VerifyRecord(result.PublicKey, reordered, signature)
+    assert.NoError(t, err, "VerifyRecord should not return an error")
+    assert.False(t, valid, "VerifyRecord should reject a modified record")
+}
@@ -0,0 +1,77 @@
+package verify
+
+import (
+    "context"
+    "runtime"
+    "sync"
+
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/utils"
+)
+
+// SignedMessage is a message and its signature submitted to VerifyStream.
+// ID is chosen by the caller and returned with the result, since results are emitted out of order.
+type SignedMessage struct {
+    ID        int
+    Message   string
+    Signature models.Signature
+}
+
+// VerifyResult is the outcome of verifying one SignedMessage in VerifyStream.
+type VerifyResult struct {
+    ID    int
+    Valid bool
+    Err   error
+}
+
+// VerifyStream verifies the signed messages received from in with a bounded pool of workers,
+// one per CPU, and emits each result as soon as it is available. Results are not in input order;
+// each carries the ID of its message.
+//
+// The returned channel is closed once in is closed and drained, or once ctx is cancelled.
+// After cancellation, messages still in flight may be dropped without a result.
+//
+// Parameters:
+//   - ctx: The context whose cancellation stops the workers.
+//   - publicKey: The public key of the system (gpk = (g1, g2, h, u, v, w)).
+//   - in: The signed messages to verify.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - <-chan VerifyResult: The results, closed when the stream ends.
+func VerifyStream(ctx context.Context, publicKey models.PublicKey, in <-chan SignedMessage, opts ...utils.HashOption) <-chan VerifyResult {
+    out := make(chan VerifyResult)
+
+    var wg sync.WaitGroup
+    for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for {
+                var msg SignedMessage
+                var ok bool
+                select {
+                case <-ctx.Done():
+                    return
+                case msg, ok = <-in:
+                    if !ok {
+                        return
+                    }
+                }
+
+                valid, err := Verify(publicKey, msg.Message, msg.Signature, opts...)
+                select {
+                case <-ctx.Done():
+                    return
+                case out <- VerifyResult{ID: msg.ID, Valid: valid, Err: err}:
+                }
+            }
+        }()
+    }
+
+    go func() {
+        wg.Wait()
+        close(out)
+    }()
+    return out
+}
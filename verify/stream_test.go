@@ -0,0 +1,67 @@
+package verify_test
+
+import (
+    "context"
+    "fmt"
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestVerifyStream tests that VerifyStream reports one result per message, matched by ID.
+func TestVerifyStream(t *testing.T) {
+    result, err := keygen.KeyGen(2)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    // Every third message is tampered and every fifth signature is malformed
+    const count = 20
+    in := make(chan verify.SignedMessage, count)
+    expected := make(map[int]bool, count)
+    for i := 0; i < count; i++ {
+        message := fmt.Sprintf("message %d", i)
+        signature, err := sign.Sign(result.PublicKey, result.Users[i%2], message)
+        assert.NoError(t, err, "Sign should not return an error")
+
+        expected[i] = true
+        if i%3 == 0 {
+            message = "tampered"
+            expected[i] = false
+        }
+        if i%5 == 0 {
+            signature = models.Signature{}
+            expected[i] = false
+        }
+        in <- verify.SignedMessage{ID: i, Message: message, Signature: signature}
+    }
+    close(in)
+
+    seen := make(map[int]bool, count)
+    for res := range verify.VerifyStream(context.Background(), result.PublicKey, in) {
+        assert.False(t, seen[res.ID], "Each message should produce one result")
+        seen[res.ID] = true
+        assert.Equal(t, expected[res.ID], res.Valid, "Message %d should have the expected result", res.ID)
+        if res.ID%5 == 0 {
+            assert.ErrorIs(t, res.Err, verify.ErrMalformedSignature, "A malformed signature should report an error")
+        } else {
+            assert.NoError(t, res.Err, "A well-formed signature should not report an error")
+        }
+    }
+    assert.Len(t, seen, count, "Every message should produce a result")
+}
+
+// TestVerifyStreamCancel tests that cancelling the context closes the result channel.
+func TestVerifyStreamCancel(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    // The input channel is never closed, so only the cancellation can end the stream
+    ctx, cancel := context.WithCancel(context.Background())
+    out := verify.VerifyStream(ctx, result.PublicKey, make(chan verify.SignedMessage))
+    cancel()
+    for range out {
+    }
+}
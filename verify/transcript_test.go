@@ -0,0 +1,49 @@
+package verify_test
+
+import (
+    "testing"
+
+    e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/models"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/utils"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestSignatureTranscriptAgreement tests that the sign and verify transcripts are byte-identical.
+func TestSignatureTranscriptAgreement(t *testing.T) {
+    result, err := keygen.KeyGen(1)
+    assert.NoError(t, err, "KeyGen should not return an error")
+    publicKey, user := result.PublicKey, result.Users[0]
+    digest := utils.DigestMessages([]string{"Hello, world!"})
+    message := digest[:]
+
+    // Sign side: compute the T and R values from fixed randomness
+    scalars, err := sign.GenerateRandomScalars(7)
+    assert.NoError(t, err, "GenerateRandomScalars should not return an error")
+    alpha, beta := scalars[0], scalars[1]
+    rAlpha, rBeta, rX, rDelta1, rDelta2 := scalars[2], scalars[3], scalars[4], scalars[5], scalars[6]
+    delta1, delta2 := sign.ComputeDeltas(alpha, beta, user.X)
+    T1, T2, T3 := sign.ComputeTValues(alpha, beta, publicKey.H, publicKey.U, publicKey.V, user.A)
+    R1, R2, R3, R4, R5, err := sign.ComputeRValues(rAlpha, rBeta, rX, rDelta1, rDelta2, T1, T2, T3, publicKey.H, publicKey.U, publicKey.V, publicKey.W, publicKey.G2)
+    assert.NoError(t, err, "ComputeRValues should not return an error")
+    signTranscript := utils.SignatureTranscript(message, T1, T2, T3, R1, R2, R3, R4, R5)
+    c, err := signTranscript.Challenge()
+    assert.NoError(t, err, "Challenge should not return an error")
+    sAlpha, sBeta, sX, sDelta1, sDelta2 := sign.ComputeSValues(alpha, beta, user.X, delta1, delta2, rAlpha, rBeta, rX, rDelta1, rDelta2, c)
+
+    // Verify side: recompute the R values from the signature
+    signature := models.Signature{T1: T1, T2: T2, T3: T3, C: c, SAlpha: sAlpha, SBeta: sBeta, SX: sX, SDelta1: sDelta1, SDelta2: sDelta2}
+    V1, V2, V3, V4, V5, err := verify.RecomputeRValues(publicKey, signature)
+    assert.NoError(t, err, "RecomputeRValues should not return an error")
+    verifyTranscript := utils.SignatureTranscript(message, signature.T1, signature.T2, signature.T3, V1, V2, V3, V4, V5)
+
+    assert.Equal(t, signTranscript.Bytes(), verifyTranscript.Bytes(), "Sign and verify transcripts should be byte-identical")
+
+    var recomputed e.Scalar
+    recomputed, err = verifyTranscript.Challenge()
+    assert.NoError(t, err, "Challenge should not return an error")
+    assert.True(t, recomputed.IsEqual(&c) == 1, "Sign and verify challenges should be equal")
+}
@@ -0,0 +1,73 @@
+package verify_test
+
+import (
+    "testing"
+
+    "github.com/aniagut/msc-bbs/keygen"
+    "github.com/aniagut/msc-bbs/sign"
+    "github.com/aniagut/msc-bbs/utils"
+    "github.com/aniagut/msc-bbs/verify"
+    "github.com/stretchr/testify/assert"
+)
+
+// TestVerifyVector tests signing and verifying a 3-field record.
+func TestVerifyVector(t *testing.T) {
+    result, err := keygen.KeyGen(2)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    record := []string{"Anna", "Gut", "1999-01-01"}
+    signature, err := sign.SignVector(result.PublicKey, result.Users[0], record)
+    assert.NoError(t, err, "SignVector should not return an error")
+
+    valid, err := verify.VerifyVector(result.PublicKey, record, signature)
+    assert.NoError(t, err, "VerifyVector should not return an error")
+    assert.True(t, valid, "VerifyVector should accept a valid vector signature")
+}
+
+// TestVerifyVectorReordered tests that reordering the fields invalidates the signature.
+func TestVerifyVectorReordered(t *testing.T) {
+    result, err := keygen.KeyGen(2)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    record := []string{"Anna", "Gut", "1999-01-01"}
+    signature, err := sign.SignVector(result.PublicKey, result.Users[0], record)
+    assert.NoError(t, err, "SignVector should not return an error")
+
+    reordered := []string{"Gut", "Anna", "1999-01-01"}
+    valid, err := verify.VerifyVector(result.PublicKey, reordered, signature)
+    assert.NoError(t, err, "VerifyVector should not return an error")
+    assert.False(t, valid, "VerifyVector should reject reordered fields")
+
+    // Concatenating the fields into one message must not verify either
+    valid, err = verify.Verify(result.PublicKey, "AnnaGut1999-01-01", signature)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.False(t, valid, "Verify should reject the concatenated record")
+}
+
+// TestVerifyVectorSingleElement tests that a single-element vector matches the single-message path.
+func TestVerifyVectorSingleElement(t *testing.T) {
+    result, err := keygen.KeyGen(2)
+    assert.NoError(t, err, "KeyGen should not return an error")
+
+    message := "Hello, world!"
+
+    // A signature over the message verifies as a single-element vector
+    signature, err := sign.Sign(result.PublicKey, result.Users[0], message)
+    assert.NoError(t, err, "Sign should not return an error")
+    valid, err := verify.VerifyVector(result.PublicKey, []string{message}, signature)
+    assert.NoError(t, err, "VerifyVector should not return an error")
+    assert.True(t, valid, "VerifyVector should accept a single-message signature")
+
+    // A single-element vector signature verifies as a message
+    signature, err = sign.SignVector(result.PublicKey, result.Users[0], []string{message})
+    assert.NoError(t, err, "SignVector should not return an error")
+    valid, err = verify.Verify(result.PublicKey, message, signature)
+    assert.NoError(t, err, "Verify should not return an error")
+    assert.True(t, valid, "Verify should accept a single-element vector signature")
+
+    // An empty vector is rejected
+    _, err = sign.SignVector(result.PublicKey, result.Users[0], nil)
+    assert.ErrorIs(t, err, utils.ErrEmptyMessageVector, "SignVector should reject an empty vector")
+    _, err = verify.VerifyVector(result.PublicKey, nil, signature)
+    assert.ErrorIs(t, err, utils.ErrEmptyMessageVector, "VerifyVector should reject an empty vector")
+}
@@ -4,144 +4,249 @@
 package verify
 
 import (
+    "encoding/hex"
+    "errors"
     "fmt"
+    "log/slog"
     e "github.com/cloudflare/circl/ecc/bls12381"
+    "github.com/aniagut/msc-bbs/internal/proof"
     "github.com/aniagut/msc-bbs/utils"
     "github.com/aniagut/msc-bbs/models"
 )
 
+// ErrMalformedSignature is returned when a signature is missing one of its required fields.
+var ErrMalformedSignature = errors.New("malformed signature")
+
 // Verify checks the validity of a BBS signature.
 //
 // Parameters:
 //   - publicKey: The public key of the system (gpk = (g1, g2, h, u, v, w)).
 //   - M: The message being verified.
 //   - signature: The BBS signature to verify.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - bool: True if the signature is valid, false otherwise.
+//   - error: ErrMalformedSignature if a field of the signature is missing, or an error if the verification process fails.
+func Verify(publicKey models.PublicKey, M string, signature models.Signature, opts ...utils.HashOption) (bool, error) {
+    valid, _, err := VerifyDetailed(publicKey, M, signature, opts...)
+    return valid, err
+}
+
+// VerifyDetailed checks the validity of a BBS signature like Verify and also returns the recomputed challenge,
+// e.g. to compare it with the signature's C or with the value computed by another implementation.
+//
+// Parameters:
+//   - publicKey: The public key of the system (gpk = (g1, g2, h, u, v, w)).
+//   - M: The message being verified.
+//   - signature: The BBS signature to verify.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - bool: True if the signature is valid, false otherwise.
+//   - e.Scalar: The recomputed challenge c, which equals signature.C for a valid signature.
+//   - error: ErrMalformedSignature if a field of the signature is missing, or an error if the verification process fails.
+func VerifyDetailed(publicKey models.PublicKey, M string, signature models.Signature, opts ...utils.HashOption) (bool, e.Scalar, error) {
+    return verifyDetailed(publicKey, utils.DigestMessages([]string{M}), signature, nil, opts, nil)
+}
+
+// VerifyVector checks the validity of a BBS signature over a vector of messages.
+// The messages must be given in the same order in which they were signed.
+//
+// Parameters:
+//   - publicKey: The public key of the system (gpk = (g1, g2, h, u, v, w)).
+//   - msgs: The messages being verified.
+//   - signature: The BBS signature to verify.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - bool: True if the signature is valid, false otherwise.
+//   - error: An error if the vector is empty or the verification process fails.
+func VerifyVector(publicKey models.PublicKey, msgs []string, signature models.Signature, opts ...utils.HashOption) (bool, error) {
+    if len(msgs) == 0 {
+        return false, utils.ErrEmptyMessageVector
+    }
+    return verifyDigest(publicKey, utils.DigestMessages(msgs), signature, opts)
+}
+
+// VerifyRecord checks the validity of a BBS signature over a record of named fields created by SignRecord.
+//
+// Parameters:
+//   - publicKey: The public key of the system (gpk = (g1, g2, h, u, v, w)).
+//   - record: The record being verified.
+//   - signature: The BBS signature to verify.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
+//
+// Returns:
+//   - bool: True if the signature is valid, false otherwise.
+//   - error: An error if the verification process fails.
+func VerifyRecord(publicKey models.PublicKey, record map[string]string, signature models.Signature, opts ...utils.HashOption) (bool, error) {
+    return verifyDigest(publicKey, utils.DigestRecord(record), signature, opts)
+}
+
+// VerifyPrehashed checks the validity of a BBS signature over a message digest computed outside of this library.
+//
+// Parameters:
+//   - publicKey: The public key of the system (gpk = (g1, g2, h, u, v, w)).
+//   - digest: The 32-byte message digest being verified.
+//   - signature: The BBS signature to verify.
+//   - opts: Optional settings for the challenge hash; it defaults to SHA-256.
 //
 // Returns:
 //   - bool: True if the signature is valid, false otherwise.
 //   - error: An error if the verification process fails.
-func Verify(publicKey models.PublicKey, M string, signature models.Signature) (bool, error) {
+func VerifyPrehashed(publicKey models.PublicKey, digest [32]byte, signature models.Signature, opts ...utils.HashOption) (bool, error) {
+    return verifyDigest(publicKey, digest, signature, opts)
+}
+
+// verifyDigest checks the validity of a BBS signature over the message digest.
+func verifyDigest(publicKey models.PublicKey, digest [32]byte, signature models.Signature, opts []utils.HashOption) (bool, error) {
+    return verifyWithExtension(publicKey, digest, signature, nil, opts, nil)
+}
+
+// transcriptExtension recomputes the additional statements bound into the challenge transcript by the signer.
+type transcriptExtension func(t *utils.Transcript, signature models.Signature)
+
+// verifyWithExtension checks the validity of a BBS signature over the message digest,
+// letting extend append further values to the challenge transcript if it is not nil.
+// If logger is not nil, the intermediate values are logged at debug level.
+func verifyWithExtension(publicKey models.PublicKey, digest [32]byte, signature models.Signature, extend transcriptExtension, opts []utils.HashOption, logger *slog.Logger) (bool, error) {
+    valid, _, err := verifyDetailed(publicKey, digest, signature, extend, opts, logger)
+    return valid, err
+}
+
+// verifyDetailed implements verifyWithExtension and also returns the recomputed challenge.
+func verifyDetailed(publicKey models.PublicKey, digest [32]byte, signature models.Signature, extend transcriptExtension, opts []utils.HashOption, logger *slog.Logger) (bool, e.Scalar, error) {
+    // Reject partially constructed signatures before dereferencing their fields
+    if err := validateSignatureShape(signature); err != nil {
+        return false, e.Scalar{}, err
+    }
+
     // Recompute the R values based on the signature and public key
-    R1 := computeR1(signature.SAlpha, publicKey.U, signature.C, signature.T1)
-    R2 := computeR2(signature.SBeta, publicKey.V, signature.C, signature.T2)
-    R3 := computeR3(signature.T3, publicKey.G1, publicKey.G2, signature.SX, publicKey.H, publicKey.W, signature.SAlpha, signature.SBeta, signature.SDelta1, signature.SDelta2, signature.C)
-    R4 := computeR4(signature.SX, signature.T1, publicKey.U, signature.SDelta1)
-    R5 := computeR5(signature.SX, signature.T2, publicKey.V, signature.SDelta2)
+    R1, R2, R3, R4, R5, err := RecomputeRValues(publicKey, signature)
+    if err != nil {
+        return false, e.Scalar{}, err
+    }
+    if logger != nil {
+        logger.Debug("recomputed R values",
+            slog.String("R1", hex.EncodeToString(utils.SerializeG1(R1))),
+            slog.String("R2", hex.EncodeToString(utils.SerializeG1(R2))),
+            slog.String("R3", hex.EncodeToString(utils.SerializeGt(R3))),
+            slog.String("R4", hex.EncodeToString(utils.SerializeG1(R4))),
+            slog.String("R5", hex.EncodeToString(utils.SerializeG1(R5))),
+        )
+    }
 
     // Compute the challenge scalar c based on the message, commitments, and R values
-    c, err := utils.HashToScalar(
-        utils.SerializeString(M),
-        utils.SerializeG1(signature.T1),
-        utils.SerializeG1(signature.T2),
-        utils.SerializeG1(signature.T3),
-        utils.SerializeG1(R1),
-        utils.SerializeG1(R2),
-        utils.SerializeGt(R3),
-        utils.SerializeG1(R4),
-        utils.SerializeG1(R5),
-    )
+    transcript := utils.SignatureTranscript(digest[:], signature.T1, signature.T2, signature.T3, R1, R2, R3, R4, R5, opts...)
+    if extend != nil {
+        extend(transcript, signature)
+    }
+    c, err := transcript.Challenge()
     if err != nil {
-        return false, fmt.Errorf("failed to compute hash to scalar: %w", err)
+        return false, e.Scalar{}, fmt.Errorf("failed to compute hash to scalar: %w", err)
     }
 
     // Verify that the recomputed challenge c matches the signature's challenge C
-    return verifySignature(c, signature.C), nil
+    valid := verifySignature(c, signature.C)
+    if logger != nil {
+        logger.Debug("compared challenges",
+            slog.String("c", c.String()),
+            slog.String("signatureC", signature.C.String()),
+            slog.Bool("equal", valid),
+        )
+    }
+    return valid, c, nil
 }
 
-// computeR1 computes R1 = u^{s_alpha} * T1^{-c}.
-func computeR1(SAlpha *e.Scalar, u *e.G1, C e.Scalar, T1 *e.G1) *e.G1 {
-    R1 := new(e.G1)
-    R1.ScalarMult(SAlpha, u)
+// validateSignatureShape checks that the T-values and s-values of the signature are set.
+func validateSignatureShape(signature models.Signature) error {
+    points := []struct {
+        name  string
+        value *e.G1
+    }{
+        {"T1", signature.T1},
+        {"T2", signature.T2},
+        {"T3", signature.T3},
+    }
+    for _, p := range points {
+        if p.value == nil {
+            return fmt.Errorf("%w: %s is missing", ErrMalformedSignature, p.name)
+        }
+    }
 
-    minusC := new(e.Scalar)
-    minusC.Set(&C)
-    minusC.Neg()
+    scalars := []struct {
+        name  string
+        value *e.Scalar
+    }{
+        {"SAlpha", signature.SAlpha},
+        {"SBeta", signature.SBeta},
+        {"SX", signature.SX},
+        {"SDelta1", signature.SDelta1},
+        {"SDelta2", signature.SDelta2},
+    }
+    for _, s := range scalars {
+        if s.value == nil {
+            return fmt.Errorf("%w: %s is missing", ErrMalformedSignature, s.name)
+        }
+    }
+    return nil
+}
 
-    T1MinusC := new(e.G1)
-    T1MinusC.ScalarMult(minusC, T1)
+// RecomputeRValues recomputes the R1, R2, R3, R4, and R5 values from the signature and the public key.
+// For a valid signature they equal the R values computed by the signer.
+func RecomputeRValues(publicKey models.PublicKey, signature models.Signature) (*e.G1, *e.G1, *e.Gt, *e.G1, *e.G1, error) {
+    R3, err := computeR3(signature.T3, publicKey.G1, publicKey.G2, signature.SX, publicKey.H, publicKey.W, signature.SAlpha, signature.SBeta, signature.SDelta1, signature.SDelta2, signature.C)
+    if err != nil {
+        return nil, nil, nil, nil, nil, err
+    }
+    R1 := computeR1(signature.SAlpha, publicKey.U, signature.C, signature.T1)
+    R2 := computeR2(signature.SBeta, publicKey.V, signature.C, signature.T2)
+    R4 := computeR4(signature.SX, signature.T1, publicKey.U, signature.SDelta1)
+    R5 := computeR5(signature.SX, signature.T2, publicKey.V, signature.SDelta2)
+    return R1, R2, R3, R4, R5, nil
+}
 
-    R1.Add(R1, T1MinusC)
-    return R1
+// computeR1 computes R1 = u^{s_alpha} * T1^{-c}.
+func computeR1(SAlpha *e.Scalar, u *e.G1, C e.Scalar, T1 *e.G1) *e.G1 {
+    return withChallenge(proof.R1(u, SAlpha), T1, C)
 }
 
 // computeR2 computes R2 = v^{s_beta} * T2^{-c}.
 func computeR2(SBeta *e.Scalar, v *e.G1, C e.Scalar, T2 *e.G1) *e.G1 {
-    R2 := new(e.G1)
-    R2.ScalarMult(SBeta, v)
+    return withChallenge(proof.R2(v, SBeta), T2, C)
+}
 
+// withChallenge computes R * T^{-c}, removing the challenge term of the statement T from R.
+func withChallenge(R, T *e.G1, C e.Scalar) *e.G1 {
     minusC := new(e.Scalar)
     minusC.Set(&C)
     minusC.Neg()
 
-    T2MinusC := new(e.G1)
-    T2MinusC.ScalarMult(minusC, T2)
+    TMinusC := new(e.G1)
+    TMinusC.ScalarMult(minusC, T)
 
-    R2.Add(R2, T2MinusC)
-    return R2
+    R.Add(R, TMinusC)
+    return R
 }
 
-// computeR3 computes R3 = e(T3, g2)^{s_x} * e(h, w)^{-s_alpha - s_beta} * e(h, g2)^{-s_delta1 - s_delta2} * (e(g1, g2) / e(T3, w))^{-c}.
-func computeR3(T3 *e.G1, g1 *e.G1, g2 *e.G2, SX *e.Scalar, h *e.G1, w *e.G2, SAlpha, SBeta, SDelta1, SDelta2 *e.Scalar, C e.Scalar) *e.Gt {
-    // Compute (-s_alpha - s_beta)
-    sAlphaBeta := new(e.Scalar)
-    sAlphaBeta.Add(SAlpha, SBeta)
-    sAlphaBeta.Neg()
-
-    // Compute (-s_delta1 - s_delta2)
-    sDelta := new(e.Scalar)
-    sDelta.Add(SDelta1, SDelta2)
-    sDelta.Neg()
-
-    // Compute {-c}
-    minusC := new(e.Scalar)
-    minusC.Set(&C)
-    minusC.Neg()
-
-    R3 := e.ProdPair(
-        []*e.G1{T3, h, h, g1, T3},
-        []*e.G2{g2, w, g2, g2, w},
-        []*e.Scalar{SX, sAlphaBeta, sDelta, minusC, &C},
-    )
-    return R3
+// computeR3 computes R3 = e(T3, g2)^{s_x} * e(h, w)^{-s_alpha - s_beta} * e(h, g2)^{-s_delta1 - s_delta2} * (e(g1, g2) / e(T3, w))^{-c}
+// with proof.R3Grouped, which groups the pairings by their G2 argument and needs two Miller loops instead of five.
+func computeR3(T3 *e.G1, g1 *e.G1, g2 *e.G2, SX *e.Scalar, h *e.G1, w *e.G2, SAlpha, SBeta, SDelta1, SDelta2 *e.Scalar, C e.Scalar) (*e.Gt, error) {
+    return proof.R3Grouped(T3, h, g1, g2, w, SX, SAlpha, SBeta, SDelta1, SDelta2, &C)
 }
 
 // computeR4 computes R4 = T1^{s_x} * u^{-s_delta1}.
 func computeR4(SX *e.Scalar, T1, u *e.G1, SDelta1 *e.Scalar) *e.G1 {
-    R4 := new(e.G1)
-    T1SX := new(e.G1)
-    T1SX.ScalarMult(SX, T1)
-
-    uSDelta1 := new(e.G1)
-    minusSDelta1 := new(e.Scalar)
-    minusSDelta1.Set(SDelta1)
-    minusSDelta1.Neg()
-    uSDelta1.ScalarMult(minusSDelta1, u)
-
-    R4.Add(T1SX, uSDelta1)
-    return R4
+    return proof.R4(T1, u, SX, SDelta1)
 }
 
 // computeR5 computes R5 = T2^{s_x} * v^{-s_delta2}.
 func computeR5(SX *e.Scalar, T2, v *e.G1, SDelta2 *e.Scalar) *e.G1 {
-    R5 := new(e.G1)
-    T2SX := new(e.G1)
-    T2SX.ScalarMult(SX, T2)
-
-    vSDelta2 := new(e.G1)
-    minusSDelta2 := new(e.Scalar)
-    minusSDelta2.Set(SDelta2)
-    minusSDelta2.Neg()
-    vSDelta2.ScalarMult(minusSDelta2, v)
-
-    R5.Add(T2SX, vSDelta2)
-    return R5
+    return proof.R5(T2, v, SX, SDelta2)
 }
 
 // verifySignature checks if the recomputed challenge c matches the signature's challenge C.
 func verifySignature(c, C e.Scalar) bool {
-    fmt.Println("Verification:")
-    fmt.Println("c: ", c)
-    fmt.Println("Signature c: ", C)
-    fmt.Println("Does c == signature.c?", c.IsEqual(&C))
-    return c.IsEqual(&C) == 1
+    return utils.ScalarsEqual(&c, &C)
 }
\ No newline at end of file
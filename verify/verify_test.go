@@ -138,7 +138,8 @@ func TestComputeR3(t *testing.T) {
     C.SetUint64(5)
 
     // Call computeR3
-    R3 := computeR3(T3, g1, g2, SX, h, w, SAlpha, SBeta, SDelta1, SDelta2, C)
+    R3, err := computeR3(T3, g1, g2, SX, h, w, SAlpha, SBeta, SDelta1, SDelta2, C)
+    assert.NoError(t, err, "computeR3 should not return an error")
 
     // Assert R3 is not nil
     assert.NotNil(t, R3, "R3 should not be nil")
@@ -196,4 +197,49 @@ func TestVerifySignature(t *testing.T) {
 
     // Assert the result is true
     assert.True(t, result, "verifySignature should return true when c equals C")
-}
\ No newline at end of file
+}
+
+// TestVerifySignatureMismatch tests that verifySignature rejects a recomputed challenge that differs from C.
+func TestVerifySignatureMismatch(t *testing.T) {
+    var a, c e.Scalar
+    a.SetUint64(42)
+    c.SetUint64(43)
+
+    assert.False(t, verifySignature(a, c), "verifySignature should return false when c differs from C")
+}
+
+// TestValidateSignatureShape tests that Verify rejects a partially constructed signature without panicking.
+func TestValidateSignatureShape(t *testing.T) {
+    publicKey := models.PublicKey{
+        G1: e.G1Generator(),
+        G2: e.G2Generator(),
+        H:  e.G1Generator(),
+        U:  e.G1Generator(),
+        V:  e.G1Generator(),
+        W:  e.G2Generator(),
+    }
+
+    // A zero-value signature is rejected
+    var valid bool
+    var err error
+    assert.NotPanics(t, func() {
+        valid, err = Verify(publicKey, "Hello, world!", models.Signature{})
+    }, "Verify should not panic on a zero-value signature")
+    assert.ErrorIs(t, err, ErrMalformedSignature, "Verify should return ErrMalformedSignature")
+    assert.False(t, valid, "Verify should reject a zero-value signature")
+
+    // A signature missing a single s-value is rejected
+    signature := models.Signature{
+        T1:      e.G1Generator(),
+        T2:      e.G1Generator(),
+        T3:      e.G1Generator(),
+        SAlpha:  new(e.Scalar),
+        SBeta:   new(e.Scalar),
+        SX:      new(e.Scalar),
+        SDelta1: new(e.Scalar),
+    }
+    err = validateSignatureShape(signature)
+    assert.ErrorIs(t, err, ErrMalformedSignature, "validateSignatureShape should reject a missing SDelta2")
+    signature.SDelta2 = new(e.Scalar)
+    assert.NoError(t, validateSignatureShape(signature), "validateSignatureShape should accept a complete signature")
+}